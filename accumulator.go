@@ -0,0 +1,169 @@
+package money
+
+/*
+The following types and functions are available
+
+Accumulator single pass (online) statistics of a stream of values
+Push adds a value
+  (a *Accumulator) Push(x float64)
+Merge combines a partial Accumulator, ex. one per goroutine
+  (a *Accumulator) Merge(b *Accumulator)
+Count, Sum, Mean, Min, Max
+Variance, VarianceSample, SD, SDs (population and sample)
+Skew, Kurtosis (population skewness and excess kurtosis)
+*/
+
+import "math"
+
+// Accumulator single pass statistics of a stream of values, too large to
+// hold in memory for Mean, SD and SDs
+// moments are updated by Welford's method extended to the third and
+// fourth moment (Pebay), the sum is Kahan compensated
+// the zero value is empty and ready to use, an Accumulator is not safe for
+// concurrent use: Push into one per goroutine and Merge the results
+type Accumulator struct {
+	n          int64
+	mean       float64
+	m2, m3, m4 float64 // sums of powers of deviations from the mean
+	sum, comp  float64 // Kahan sum and compensation
+	min, max   float64
+}
+
+// Push adds x to the accumulator
+func (a *Accumulator) Push(x float64) {
+	if a.n == 0 {
+		a.min, a.max = x, x
+	}
+	a.min = math.Min(a.min, x)
+	a.max = math.Max(a.max, x)
+	a.kahan(x)
+	n1 := float64(a.n)
+	a.n++
+	n := float64(a.n)
+	delta := x - a.mean
+	dn := delta / n
+	dn2 := dn * dn
+	term := delta * dn * n1
+	a.mean += dn
+	a.m4 += term*dn2*(n*n-3*n+3) + 6*dn2*a.m2 - 4*dn*a.m3
+	a.m3 += term*dn*(n-2) - 3*dn*a.m2
+	a.m2 += term
+}
+
+// kahan adds x to the compensated sum
+func (a *Accumulator) kahan(x float64) {
+	y := x - a.comp
+	t := a.sum + y
+	a.comp = (t - a.sum) - y
+	a.sum = t
+}
+
+// Merge combines b into a, as if every value pushed to b had been pushed
+// to a (Chan and Pebay pairwise formulas)
+func (a *Accumulator) Merge(b *Accumulator) {
+	if b.n == 0 {
+		return
+	}
+	if a.n == 0 {
+		*a = *b
+		return
+	}
+	na, nb := float64(a.n), float64(b.n)
+	n := na + nb
+	d := b.mean - a.mean
+	d2 := d * d
+	m2 := a.m2 + b.m2 + d2*na*nb/n
+	m3 := a.m3 + b.m3 + d2*d*na*nb*(na-nb)/(n*n) +
+		3*d*(na*b.m2-nb*a.m2)/n
+	m4 := a.m4 + b.m4 + d2*d2*na*nb*(na*na-na*nb+nb*nb)/(n*n*n) +
+		6*d2*(na*na*b.m2+nb*nb*a.m2)/(n*n) + 4*d*(na*b.m3-nb*a.m3)/n
+	a.mean += d * nb / n
+	a.m2, a.m3, a.m4 = m2, m3, m4
+	a.n += b.n
+	a.kahan(b.sum)
+	a.kahan(-b.comp)
+	a.min = math.Min(a.min, b.min)
+	a.max = math.Max(a.max, b.max)
+}
+
+// Count number of values pushed
+func (a *Accumulator) Count() int64 {
+	return a.n
+}
+
+// Sum total of the values (compensated)
+func (a *Accumulator) Sum() float64 {
+	return a.sum
+}
+
+// Mean Average of the values
+func (a *Accumulator) Mean() float64 {
+	if a.n == 0 {
+		panic(NOOR)
+	}
+	return a.mean
+}
+
+// Min smallest value
+func (a *Accumulator) Min() float64 {
+	if a.n == 0 {
+		panic(NOOR)
+	}
+	return a.min
+}
+
+// Max largest value
+func (a *Accumulator) Max() float64 {
+	if a.n == 0 {
+		panic(NOOR)
+	}
+	return a.max
+}
+
+// Variance population variance
+// var = SIGMA ((a[i] - mean) ^ 2) / n
+func (a *Accumulator) Variance() float64 {
+	if a.n == 0 {
+		panic(NOOR)
+	}
+	return a.m2 / float64(a.n)
+}
+
+// VarianceSample sample variance
+// var = SIGMA ((a[i] - mean) ^ 2) / (n - 1)
+func (a *Accumulator) VarianceSample() float64 {
+	if a.n < 2 {
+		panic(NOOR)
+	}
+	return a.m2 / float64(a.n-1)
+}
+
+// SD Standard Deviation (see SD)
+func (a *Accumulator) SD() float64 {
+	return math.Sqrt(a.Variance())
+}
+
+// SDs Standard Deviation of a sample (see SDs)
+func (a *Accumulator) SDs() float64 {
+	return math.Sqrt(a.VarianceSample())
+}
+
+// Skew population skewness
+// g1 = (SIGMA ((a[i] - mean) ^ 3) / n) / sd^3
+func (a *Accumulator) Skew() float64 {
+	if a.n == 0 {
+		panic(NOOR)
+	}
+	n := float64(a.n)
+	return math.Sqrt(n) * a.m3 / math.Pow(a.m2, 1.5)
+}
+
+// Kurtosis population excess kurtosis
+// g2 = (SIGMA ((a[i] - mean) ^ 4) / n) / sd^4 - 3
+func (a *Accumulator) Kurtosis() float64 {
+	if a.n == 0 {
+		panic(NOOR)
+	}
+	n := float64(a.n)
+	return n*a.m4/(a.m2*a.m2) - 3
+}
@@ -0,0 +1,168 @@
+package money
+
+/*
+The following functions are available
+
+BarrierBSM single barrier knock-in and knock-out options (Reiner-Rubinstein)
+  BarrierBSM(s, k, h, rebate, t, r, q, v float64, o OptionType, b Barrier) float64
+CashOrNothing digital paying a fixed cash amount
+  CashOrNothing(s, k, cash, t, r, q, v float64, o OptionType) float64
+AssetOrNothing digital paying the asset
+  AssetOrNothing(s, k, t, r, q, v float64, o OptionType) float64
+*/
+
+import "math"
+
+// BarrierBSM single barrier option, continuously monitored (Reiner-Rubinstein)
+// s = Spot price
+// k = strike price
+// h = barrier level
+// rebate = cash paid at expiry if an in option is never knocked in, or at
+// the hit if an out option is knocked out
+// t, r, q, v = as for BSM
+// o = Call or Put
+// b = DownIn, DownOut, UpIn or UpOut
+// with no rebate in + out = BSM
+// mu = (r - q - v^2/2) / v^2
+// lambda = (mu^2 + 2r / v^2)^1/2
+// x1 = ln(s/k) / (v * t^1/2) + (1 + mu) * v * t^1/2
+// x2 = ln(s/h) / (v * t^1/2) + (1 + mu) * v * t^1/2
+// y1 = ln(h^2 / (s * k)) / (v * t^1/2) + (1 + mu) * v * t^1/2
+// y2 = ln(h/s) / (v * t^1/2) + (1 + mu) * v * t^1/2
+// z  = ln(h/s) / (v * t^1/2) + lambda * v * t^1/2
+// phi = 1 for a call, -1 for a put, eta = 1 for down, -1 for up
+// A = phi * s * e^(-qt) * N(phi*x1) - phi * k * e^(-rt) * N(phi*x1 - phi*v*t^1/2)
+// B = phi * s * e^(-qt) * N(phi*x2) - phi * k * e^(-rt) * N(phi*x2 - phi*v*t^1/2)
+// C = phi * s * e^(-qt) * (h/s)^(2(mu+1)) * N(eta*y1) - phi * k * e^(-rt) * (h/s)^(2mu) * N(eta*y1 - eta*v*t^1/2)
+// D = phi * s * e^(-qt) * (h/s)^(2(mu+1)) * N(eta*y2) - phi * k * e^(-rt) * (h/s)^(2mu) * N(eta*y2 - eta*v*t^1/2)
+// E = rebate * e^(-rt) * (N(eta*x2 - eta*v*t^1/2) - (h/s)^(2mu) * N(eta*y2 - eta*v*t^1/2))
+// F = rebate * ((h/s)^(mu+lambda) * N(eta*z) + (h/s)^(mu-lambda) * N(eta*z - 2*eta*lambda*v*t^1/2))
+// a barrier already breached gives BSM for an in option and the rebate
+// for an out option
+func BarrierBSM(s, k, h, rebate, t, r, q, v float64, o OptionType, b Barrier) float64 {
+	if s <= 0 || k <= 0 || h <= 0 || t < 0 || v < 0 {
+		panic(NOOR)
+	}
+	if o != Call && o != Put {
+		panic(NOOR)
+	}
+	down := b == DownIn || b == DownOut
+	in := b == DownIn || b == UpIn
+	if down && s <= h || !down && s >= h {
+		if in {
+			return BSM(s, k, t, r, q, v, o)
+		}
+		return rebate
+	}
+	vt := v * math.Sqrt(t)
+	if vt == 0 {
+		// the path is the forward s * e^((r-q)t), which may drift to h
+		fwd := s * math.Exp((r-q)*t)
+		if down && fwd > h || !down && fwd < h {
+			if in {
+				return rebate * math.Exp(-r*t)
+			}
+			return BSM(s, k, t, r, q, v, o)
+		}
+		if in {
+			return BSM(s, k, t, r, q, v, o)
+		}
+		return rebate * math.Exp(-r*math.Log(h/s)/(r-q))
+	}
+	phi, eta := 1.0, 1.0
+	if o == Put {
+		phi = -1
+	}
+	if !down {
+		eta = -1
+	}
+	mu := (r - q - v*v/2) / (v * v)
+	lambda := math.Sqrt(mu*mu + 2*r/(v*v))
+	x1 := math.Log(s/k)/vt + (1+mu)*vt
+	x2 := math.Log(s/h)/vt + (1+mu)*vt
+	y1 := math.Log(h*h/(s*k))/vt + (1+mu)*vt
+	y2 := math.Log(h/s)/vt + (1+mu)*vt
+	z := math.Log(h/s)/vt + lambda*vt
+	sq := s * math.Exp(-q*t)
+	kr := k * math.Exp(-r*t)
+	hs := h / s
+	A := phi*sq*CND(phi*x1) - phi*kr*CND(phi*x1-phi*vt)
+	B := phi*sq*CND(phi*x2) - phi*kr*CND(phi*x2-phi*vt)
+	C := phi*sq*math.Pow(hs, 2*(mu+1))*CND(eta*y1) - phi*kr*math.Pow(hs, 2*mu)*CND(eta*y1-eta*vt)
+	D := phi*sq*math.Pow(hs, 2*(mu+1))*CND(eta*y2) - phi*kr*math.Pow(hs, 2*mu)*CND(eta*y2-eta*vt)
+	E := rebate * math.Exp(-r*t) * (CND(eta*x2-eta*vt) - math.Pow(hs, 2*mu)*CND(eta*y2-eta*vt))
+	F := rebate * (math.Pow(hs, mu+lambda)*CND(eta*z) + math.Pow(hs, mu-lambda)*CND(eta*z-2*eta*lambda*vt))
+	above := k > h
+	switch {
+	case b == DownIn && o == Call && above, b == UpIn && o == Put && !above:
+		return C + E
+	case b == DownIn && o == Call, b == UpIn && o == Put:
+		return A - B + D + E
+	case b == UpIn && o == Call && above, b == DownIn && o == Put && !above:
+		return A + E
+	case b == UpIn && o == Call, b == DownIn && o == Put:
+		return B - C + D + E
+	case b == DownOut && o == Call && above, b == UpOut && o == Put && !above:
+		return A - C + F
+	case b == DownOut && o == Call, b == UpOut && o == Put:
+		return B - D + F
+	case b == UpOut && o == Call && above, b == DownOut && o == Put && !above:
+		return F
+	}
+	// UpOut call or DownOut put with the strike inside the barrier
+	return A - B + C - D + F
+}
+
+// CashOrNothing digital option paying cash if it expires in the money
+// call = cash * e^(-rt) * N(d2), put = cash * e^(-rt) * N(-d2)
+// d2 = (ln(s/k) + (r - q - v^2/2)t) / (v * t^1/2)
+// cash = amount paid
+// s, k, t, r, q, v = as for BSM
+func CashOrNothing(s, k, cash, t, r, q, v float64, o OptionType) float64 {
+	_, d2 := digitalD(s, k, t, r, q, v, o)
+	return cash * math.Exp(-r*t) * CND(d2)
+}
+
+// AssetOrNothing digital option paying the asset if it expires in the money
+// call = s * e^(-qt) * N(d1), put = s * e^(-qt) * N(-d1)
+// d1 = (ln(s/k) + (r - q + v^2/2)t) / (v * t^1/2)
+// s, k, t, r, q, v = as for BSM
+// a call less a cash-or-nothing call paying k is BSM
+func AssetOrNothing(s, k, t, r, q, v float64, o OptionType) float64 {
+	d1, _ := digitalD(s, k, t, r, q, v, o)
+	return s * math.Exp(-q*t) * CND(d1)
+}
+
+// digitalD returns d1 and d2 signed for o (negated for a put), infinite
+// when t or v is zero so that N() is 0 or 1 on the forward moneyness
+func digitalD(s, k, t, r, q, v float64, o OptionType) (d1, d2 float64) {
+	if s <= 0 || k <= 0 || t < 0 || v < 0 {
+		panic(NOOR)
+	}
+	if o != Call && o != Put {
+		panic(NOOR)
+	}
+	sign := 1.0
+	if o == Put {
+		sign = -1
+	}
+	vt := v * math.Sqrt(t)
+	if vt == 0 {
+		m := math.Log(s/k) + (r-q)*t
+		if m == 0 {
+			return 0, 0
+		}
+		return math.Inf(int(sign) * sgn(m)), math.Inf(int(sign) * sgn(m))
+	}
+	d1 = (math.Log(s/k) + (r-q+v*v/2)*t) / vt
+	d2 = d1 - vt
+	return sign * d1, sign * d2
+}
+
+// sgn sign of x as an int (1 for zero)
+func sgn(x float64) int {
+	if x < 0 {
+		return -1
+	}
+	return 1
+}
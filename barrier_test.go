@@ -0,0 +1,94 @@
+package money
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBarrierHaug(t *testing.T) {
+	// Haug, The Complete Guide to Option Pricing Formulas, table 4-13
+	// s = 100, t = 0.5, r = 0.08, q = 0.04, v = 0.25, rebate = 3
+	tests := []struct {
+		k, h float64
+		o    OptionType
+		b    Barrier
+		want float64
+	}{
+		{90, 95, Call, DownOut, 9.0246},
+		{100, 95, Call, DownOut, 6.7924},
+		{110, 95, Call, DownOut, 4.8759},
+		{90, 100, Call, DownOut, 3},
+		{90, 105, Call, UpOut, 2.6789},
+		{100, 105, Call, UpOut, 2.3580},
+		{110, 105, Call, UpOut, 2.3453},
+		{90, 95, Call, DownIn, 7.7627},
+		{100, 95, Call, DownIn, 4.0109},
+		{110, 95, Call, DownIn, 2.0576},
+		{90, 105, Call, UpIn, 14.1112},
+		{100, 105, Call, UpIn, 8.4482},
+		{110, 105, Call, UpIn, 4.5910},
+		{90, 95, Put, DownOut, 2.2798},
+		{100, 95, Put, DownOut, 2.2947},
+		{110, 95, Put, DownOut, 2.6252},
+		{90, 105, Put, UpOut, 3.7760},
+		{100, 105, Put, UpOut, 5.4932},
+		{110, 105, Put, UpOut, 7.5187},
+		{90, 95, Put, DownIn, 2.9586},
+		{100, 95, Put, DownIn, 6.5677},
+		{110, 95, Put, DownIn, 11.9752},
+		{90, 105, Put, UpIn, 1.4653},
+		{100, 105, Put, UpIn, 3.3721},
+		{110, 105, Put, UpIn, 7.0846},
+	}
+	for _, tt := range tests {
+		got := BarrierBSM(100, tt.k, tt.h, 3, 0.5, 0.08, 0.04, 0.25, tt.o, tt.b)
+		if math.Abs(got-tt.want) > 5e-5 {
+			t.Errorf("%v %v k %v h %v = %v, want %v", tt.b, tt.o, tt.k, tt.h, got, tt.want)
+		}
+	}
+}
+
+func TestBarrierParity(t *testing.T) {
+	// with no rebate in + out = BSM
+	tests := []struct {
+		s, k, h, t, r, q, v float64
+		in, out             Barrier
+	}{
+		{100, 90, 95, 0.5, 0.08, 0.04, 0.25, DownIn, DownOut},
+		{100, 100, 80, 1, 0.03, 0, 0.4, DownIn, DownOut},
+		{100, 110, 105, 0.5, 0.08, 0.04, 0.25, UpIn, UpOut},
+		{100, 100, 130, 2, 0.05, 0.02, 0.3, UpIn, UpOut},
+		{100, 120, 110, 1, 0.05, 0.02, 0.3, UpIn, UpOut},
+		{100, 80, 90, 1, 0.05, 0.02, 0.3, DownIn, DownOut},
+	}
+	for _, tt := range tests {
+		for _, o := range []OptionType{Call, Put} {
+			in := BarrierBSM(tt.s, tt.k, tt.h, 0, tt.t, tt.r, tt.q, tt.v, o, tt.in)
+			out := BarrierBSM(tt.s, tt.k, tt.h, 0, tt.t, tt.r, tt.q, tt.v, o, tt.out)
+			want := BSM(tt.s, tt.k, tt.t, tt.r, tt.q, tt.v, o)
+			if math.Abs(in+out-want) > 1e-10 {
+				t.Errorf("%v %v in %v + out %v = %v, BSM %v", tt, o, in, out, in+out, want)
+			}
+		}
+	}
+}
+
+func TestDigital(t *testing.T) {
+	// Haug examples
+	if got := CashOrNothing(100, 80, 10, 0.75, 0.06, 0.06, 0.35, Put); math.Abs(got-2.6710) > 5e-5 {
+		t.Errorf("CashOrNothing = %v, want 2.6710", got)
+	}
+	if got := AssetOrNothing(70, 65, 0.5, 0.07, 0.05, 0.27, Put); math.Abs(got-20.2069) > 5e-5 {
+		t.Errorf("AssetOrNothing = %v, want 20.2069", got)
+	}
+	// an asset or nothing less a cash or nothing paying k is the vanilla
+	for _, o := range []OptionType{Call, Put} {
+		got := AssetOrNothing(42, 40, 0.5, 0.1, 0.03, 0.2, o) - CashOrNothing(42, 40, 40, 0.5, 0.1, 0.03, 0.2, o)
+		if o == Put {
+			got = -got
+		}
+		if want := BSM(42, 40, 0.5, 0.1, 0.03, 0.2, o); math.Abs(got-want) > 1e-10 {
+			t.Errorf("%v digitals = %v, BSM %v", o, got, want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package money
+
+/*
+The following functions are available
+
+series holds one slice of aligned returns per asset, series[i][t] is the
+return of asset i in period t, with NaN marking a missing value
+
+CovMatrix covariance matrix (population or sample), pairwise complete
+  CovMatrix(series [][]float64, sample bool) [][]float64
+CorrMatrix correlation matrix, pairwise complete
+  CorrMatrix(series [][]float64) [][]float64
+CovToCorr correlation matrix of a covariance matrix
+  CovToCorr(cov [][]float64) [][]float64
+LedoitWolf covariance shrunk towards a scaled identity, and the shrinkage
+  LedoitWolf(series [][]float64) ([][]float64, float64)
+*/
+
+import "math"
+
+// pairMoments count and co-moments of x and y over the periods where
+// both are present (pairwise complete)
+func pairMoments(x, y []float64) (n, cxy, cxx, cyy float64) {
+	if len(x) != len(y) {
+		panic(NOOR)
+	}
+	var m window
+	for t := range x {
+		if math.IsNaN(x[t]) || math.IsNaN(y[t]) {
+			continue
+		}
+		m.add(x[t], y[t])
+	}
+	return m.n, m.cxy, m.cxx, m.cyy
+}
+
+// CovMatrix covariance matrix of N series
+// cov[i][j] = SIGMA ((x[i] - mean x) * (y[j] - mean y)) / n (n - 1 if sample)
+// each pair uses the periods where both series have a value, so the
+// matrix need not be positive semi-definite when values are missing
+func CovMatrix(series [][]float64, sample bool) [][]float64 {
+	if len(series) == 0 {
+		panic(NOOR)
+	}
+	N := len(series)
+	cov := square(N)
+	for i := 0; i < N; i++ {
+		for j := i; j < N; j++ {
+			n, cxy, _, _ := pairMoments(series[i], series[j])
+			d := n
+			if sample {
+				d--
+			}
+			c := math.NaN()
+			if d > 0 {
+				c = cxy / d
+			}
+			cov[i][j], cov[j][i] = c, c
+		}
+	}
+	return cov
+}
+
+// CorrMatrix correlation matrix of N series, pairwise complete
+// corr[i][j] = cov(i, j) / (sd(i) * sd(j)) over the common periods
+func CorrMatrix(series [][]float64) [][]float64 {
+	if len(series) == 0 {
+		panic(NOOR)
+	}
+	N := len(series)
+	corr := square(N)
+	for i := 0; i < N; i++ {
+		corr[i][i] = 1
+		for j := i + 1; j < N; j++ {
+			_, cxy, cxx, cyy := pairMoments(series[i], series[j])
+			c := math.NaN()
+			if d := math.Sqrt(cxx * cyy); d > 0 {
+				c = cxy / d
+			}
+			corr[i][j], corr[j][i] = c, c
+		}
+	}
+	return corr
+}
+
+// CovToCorr correlation matrix of a covariance matrix
+// corr[i][j] = cov[i][j] / (cov[i][i] * cov[j][j])^1/2
+func CovToCorr(cov [][]float64) [][]float64 {
+	N := len(cov)
+	corr := square(N)
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			corr[i][j] = cov[i][j] / math.Sqrt(cov[i][i]*cov[j][j])
+		}
+	}
+	return corr
+}
+
+// LedoitWolf covariance shrunk towards a scaled identity (Ledoit-Wolf 2004)
+// s = population covariance matrix, m = tr(s) / N
+// d^2 = ||s - m * I||^2
+// b^2 = min(SIGMA (t) ||x(t) * x(t)' - s||^2 / T^2, d^2), x(t) demeaned returns
+// shrink = b^2 / d^2
+// cov = shrink * m * I + (1 - shrink) * s
+// ||A||^2 = SIGMA (i,j) A[i][j]^2 / N
+// only periods where every series has a value are used
+// returns the shrunk matrix and the shrinkage intensity (0 to 1)
+func LedoitWolf(series [][]float64) ([][]float64, float64) {
+	x := completeRows(series)
+	N := len(x)
+	T := len(x[0])
+	if T < 2 {
+		panic(NOOR)
+	}
+	for i := range x {
+		mu := Mean(x[i])
+		for t := range x[i] {
+			x[i][t] -= mu
+		}
+	}
+	s := square(N)
+	for i := 0; i < N; i++ {
+		for j := i; j < N; j++ {
+			var c float64
+			for t := 0; t < T; t++ {
+				c += x[i][t] * x[j][t]
+			}
+			s[i][j], s[j][i] = c/float64(T), c/float64(T)
+		}
+	}
+	var m float64
+	for i := 0; i < N; i++ {
+		m += s[i][i]
+	}
+	m /= float64(N)
+	var d2 float64
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			d := s[i][j]
+			if i == j {
+				d -= m
+			}
+			d2 += d * d
+		}
+	}
+	d2 /= float64(N)
+	var b2 float64
+	for t := 0; t < T; t++ {
+		for i := 0; i < N; i++ {
+			for j := 0; j < N; j++ {
+				d := x[i][t]*x[j][t] - s[i][j]
+				b2 += d * d
+			}
+		}
+	}
+	b2 /= float64(N) * float64(T) * float64(T)
+	b2 = math.Min(b2, d2)
+	shrink := 0.0
+	if d2 > 0 {
+		shrink = b2 / d2
+	}
+	for i := 0; i < N; i++ {
+		for j := 0; j < N; j++ {
+			s[i][j] *= 1 - shrink
+		}
+		s[i][i] += shrink * m
+	}
+	return s, shrink
+}
+
+// completeRows copies series keeping only the periods where every series
+// has a value
+func completeRows(series [][]float64) [][]float64 {
+	if len(series) == 0 {
+		panic(NOOR)
+	}
+	T := len(series[0])
+	x := make([][]float64, len(series))
+	for i := range series {
+		if len(series[i]) != T {
+			panic(NOOR)
+		}
+	}
+	for t := 0; t < T; t++ {
+		ok := true
+		for i := range series {
+			if math.IsNaN(series[i][t]) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			for i := range series {
+				x[i] = append(x[i], series[i][t])
+			}
+		}
+	}
+	return x
+}
+
+// square returns an n by n matrix of zeros
+func square(n int) [][]float64 {
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+	}
+	return m
+}
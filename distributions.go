@@ -0,0 +1,203 @@
+package money
+
+/*
+The following functions are available
+
+CND Cumulative standard normal distribution N(x)
+  CND(x float64) float64
+ND Standard normal density n(x)
+  ND(x float64) float64
+ICND Inverse cumulative standard normal distribution (quantile)
+  ICND(p float64) float64
+TCD Cumulative Student t distribution with df degrees of freedom
+  TCD(x, df float64) float64
+Chi2CD Cumulative chi-square distribution with k degrees of freedom
+  Chi2CD(x, k float64) float64
+*/
+
+import "math"
+
+// CND Cumulative standard normal distribution
+// N(x) = (1 + erf(x / sqrt(2))) / 2
+// computed through erfc to keep precision in the lower tail
+func CND(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// ND Standard normal density
+// n(x) = e ^ (-x^2 / 2) / sqrt(2 * pi)
+func ND(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
+
+// ICND Inverse cumulative standard normal distribution, the x with
+// N(x) = p, ex. ICND(0.99) = 2.326
+// Acklam's rational approximation refined by one Halley step
+func ICND(p float64) float64 {
+	if p <= 0 || p >= 1 || math.IsNaN(p) {
+		switch p {
+		case 0:
+			return math.Inf(-1)
+		case 1:
+			return math.Inf(1)
+		}
+		panic(NOOR)
+	}
+	a := [6]float64{-3.969683028665376e+01, 2.209460984245205e+02,
+		-2.759285104469687e+02, 1.383577518672690e+02,
+		-3.066479806614716e+01, 2.506628277459239e+00}
+	b := [5]float64{-5.447609879822406e+01, 1.615858368580409e+02,
+		-1.556989798598866e+02, 6.680131188771972e+01,
+		-1.328068155288572e+01}
+	c := [6]float64{-7.784894002430293e-03, -3.223964580411365e-01,
+		-2.400758277161838e+00, -2.549732539343734e+00,
+		4.374664141464968e+00, 2.938163982698783e+00}
+	d := [4]float64{7.784695709041462e-03, 3.224671290700398e-01,
+		2.445134137142996e+00, 3.754408661907416e+00}
+	const low = 0.02425
+	var x float64
+	switch {
+	case p < low:
+		q := math.Sqrt(-2 * math.Log(p))
+		x = (((((c[0]*q+c[1])*q+c[2])*q+c[3])*q+c[4])*q + c[5]) /
+			((((d[0]*q+d[1])*q+d[2])*q+d[3])*q + 1)
+	case p > 1-low:
+		q := math.Sqrt(-2 * math.Log1p(-p))
+		x = -(((((c[0]*q+c[1])*q+c[2])*q+c[3])*q+c[4])*q + c[5]) /
+			((((d[0]*q+d[1])*q+d[2])*q+d[3])*q + 1)
+	default:
+		q := p - 0.5
+		r := q * q
+		x = (((((a[0]*r+a[1])*r+a[2])*r+a[3])*r+a[4])*r + a[5]) * q /
+			(((((b[0]*r+b[1])*r+b[2])*r+b[3])*r+b[4])*r + 1)
+	}
+	e := CND(x) - p
+	u := e / ND(x)
+	return x - u/(1+x*u/2)
+}
+
+// TCD Cumulative Student t distribution with df degrees of freedom
+// T(x) = 1 - I(df / (df + x^2); df / 2, 1 / 2) / 2 for x >= 0
+// I = regularized incomplete beta function
+func TCD(x, df float64) float64 {
+	if df <= 0 {
+		panic(NOOR)
+	}
+	tail := betaInc(df/2, 0.5, df/(df+x*x)) / 2
+	if x < 0 {
+		return tail
+	}
+	return 1 - tail
+}
+
+// Chi2CD Cumulative chi-square distribution with k degrees of freedom
+// F(x) = P(k / 2, x / 2)
+// P = regularized lower incomplete gamma function
+func Chi2CD(x, k float64) float64 {
+	if k <= 0 {
+		panic(NOOR)
+	}
+	return gammaInc(k/2, x/2)
+}
+
+// gammaInc regularized lower incomplete gamma function P(a, x), by its
+// series below a + 1 and its continued fraction (Lentz) above
+func gammaInc(a, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if math.IsInf(x, 1) {
+		return 1
+	}
+	lg, _ := math.Lgamma(a)
+	front := math.Exp(a*math.Log(x) - x - lg)
+	if x < a+1 {
+		sum, term := 1/a, 1/a
+		for n := 1.0; n < 1000; n++ {
+			term *= x / (a + n)
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*1e-16 {
+				break
+			}
+		}
+		return front * sum
+	}
+	const tiny = 1e-300
+	b := x + 1 - a
+	c, d := 1/tiny, 1/b
+	h := d
+	for n := 1.0; n < 1000; n++ {
+		an := -n * (n - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+		if math.Abs(d*c-1) < 1e-16 {
+			break
+		}
+	}
+	return 1 - front*h
+}
+
+// betaInc regularized incomplete beta function I(x; a, b), evaluated by
+// its continued fraction (Lentz), using the symmetry
+// I(x; a, b) = 1 - I(1 - x; b, a) where that converges faster
+func betaInc(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log1p(-x))
+	if x > (a+1)/(a+b+2) {
+		return 1 - front*betaCF(b, a, 1-x)/b
+	}
+	return front * betaCF(a, b, x) / a
+}
+
+// betaCF continued fraction of the incomplete beta function
+func betaCF(a, b, x float64) float64 {
+	const tiny = 1e-300
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= 300; m++ {
+		fm := float64(m)
+		for i := 0; i < 2; i++ {
+			var aa float64
+			if i == 0 {
+				aa = fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+			} else {
+				aa = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+			}
+			d = 1 + aa*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + aa/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < 1e-15 {
+			break
+		}
+	}
+	return h
+}
@@ -1,6 +1,9 @@
 package money
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 // parsedTime is the struct representing a parsed time value.
 type parsedTime struct {
@@ -53,6 +56,46 @@ const ( // for GetQuote
 	QUOTEFAIL   string = "Bad read or quote not found for "
 )
 
+const ( // for YieldCurve
+	BOOTMAT  string = "Instrument maturity not after previous knot "
+	BOOTFAIL string = "Unable to bootstrap curve at "
+	BOOTFREQ string = "Swap frequency must be 1, 2, 3, 4, 6 or 12, not "
+)
+
+const ( // for ImpliedVol
+	IVLOW  string = "Option price below its arbitrage lower bound"
+	IVHIGH string = "Option price at or above its arbitrage upper bound"
+	IVFAIL string = "Implied volatility did not converge"
+)
+
+const ( // for FitGARCH
+	GARCHFAIL string = "GARCH likelihood could not be maximised"
+)
+
+const ( // for Regress
+	OLSRANK string = "Regressors are collinear or too few observations"
+)
+
+const ( // for MeanVariance
+	MVFAIL   string = "Portfolio constraints infeasible or optimiser did not converge"
+	MVSHARPE string = "No asset returns more than the risk free rate"
+)
+
+const ( // for RiskParity
+	RPFAIL string = "Risk parity weights did not converge"
+)
+
+const ( // for Ledger
+	LOTQTY string = "Not enough shares in lots to relieve "
+	LOTID  string = "Lot not held, named twice or none named for "
+)
+
+var ( // for ImpliedVol
+	ErrIVLow  = errors.New(IVLOW)
+	ErrIVHigh = errors.New(IVHIGH)
+	ErrIVFail = errors.New(IVFAIL)
+)
+
 // DecimalChange resets the package-wide decimal place (default is 2 decimal places)
 func DecimalChange(d int) {
 	if d < 0 {
@@ -7,6 +7,8 @@ The following functions are available
 
 Black-Scholes (European put and call options)
   BS(s, k, t, r, v float64, putcall string) float64
+Black-Scholes-Merton (European options with dividend yield, see options.go)
+  BSM(s, k, t, r, q, v float64, o OptionType) float64
 CMP Compounded Interest Rate
   CMP(fv, pv *Money, n float64) float64
 CNI Continuous Interest
@@ -57,6 +59,8 @@ SD Standard Deviation
   SD(a []float64) float64
 SDs Standard Deviation of a sample
   SDs(a []float64) float64
+Median, Quantile, Skewness, Kurtosis, WeightedMean, Mode, Histogram (see stats.go)
+  Quantile(a []float64, p float64, m QuantileMethod) float64
 
 
 
@@ -64,27 +68,24 @@ SDs Standard Deviation of a sample
 
 // Black-Scholes (European put and call options)
 // C Theoretical call premium (non-dividend paying stock)
-// c = sn(d1) - ke^(-rt)N(d2)
-// d1 = ln(s/k) + (r + s^2/2)t
-// d2 = d1- st^1/2
-// v = stock value
+// c = s * N(d1) - k * e^(-rt) * N(d2)
+// d1 = (ln(s/k) + (r + v^2/2)t) / (v * t^1/2)
+// d2 = d1 - v * t^1/2
 // k = Stock strike price
 // s = Spot price
 // t = time to expire in years
 // r = risk free rate
 // v = volitilaty (sigma)
-// e math.E 2.7183
+// N = cumulative normal distribution (CND)
 // putcall = "c" for a call or "p" for a put
+// returns NaN for any other putcall or for s <= 0, k <= 0, t < 0 or v < 0
+// where BSM panics, see BSM for dividends and OptionType
 func BS(s, k, t, r, v float64, putcall string) float64 {
-	d1 := (math.Log(s/k) + ((r + (math.Pow(v, 2) / 2)) * t)) / (v * math.Sqrt(t))
-	d2 := d1 - (v * math.Sqrt(t))
-	if putcall == call {
-		return math.Erf(d1)*s - (math.Erf(d2) * k * math.Pow(math.E, (-1*r*t)))
+	o, ok := optionType(putcall)
+	if !ok || s <= 0 || k <= 0 || t < 0 || v < 0 {
+		return math.NaN()
 	}
-	if putcall == put {
-		return k*math.Pow(math.E, (-1*r*t)) - s + ((math.Erf(-1*d2) * k * math.Pow(math.E, (-1*r*t))) - (math.Erf(-1*d1) * s))
-	}
-	panic(NOOR)
+	return BSM(s, k, t, r, 0, v, o)
 }
 
 // CMP Compounded Interest Rate
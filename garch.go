@@ -0,0 +1,195 @@
+package money
+
+/*
+The following types and functions are available
+
+GARCH fitted GARCH(1,1) or GJR-GARCH(1,1) variance model
+FitGARCH maximum likelihood fit to a return series
+  FitGARCH(returns []float64, gjr bool) (*GARCH, error)
+Persistence alpha + beta + gamma/2
+  (g *GARCH) Persistence() float64
+LongRunVar unconditional variance omega / (1 - persistence)
+  (g *GARCH) LongRunVar() float64
+Forecast variance forecasts for the next h periods
+  (g *GARCH) Forecast(h int) []float64
+ForecastVol annualised volatility over the next h periods
+  (g *GARCH) ForecastVol(h int, n float64) float64
+*/
+
+import (
+	"errors"
+	"math"
+)
+
+// GARCH is a fitted GARCH(1,1) or GJR-GARCH(1,1) model
+// e(t) = r(t) - Mu
+// v^2(t) = Omega + (Alpha + Gamma * I(e(t-1) < 0)) * e^2(t-1) + Beta * v^2(t-1)
+// Gamma is zero unless GJR
+// StdErr = standard errors of Omega, Alpha, Beta and Gamma
+// LogLik = Gaussian log likelihood at the fit
+// Next = variance forecast for the period after the last return
+type GARCH struct {
+	Mu, Omega, Alpha, Beta, Gamma float64
+	GJR                           bool
+	StdErr                        []float64
+	LogLik                        float64
+	Next                          float64
+}
+
+// FitGARCH fits a GARCH(1,1), or GJR-GARCH(1,1) when gjr, to returns by
+// maximum likelihood with normal innovations
+// returns = periodic returns, oldest first
+// Mu is the sample mean and v^2(0) the sample variance, the likelihood
+// is maximised by Nelder-Mead under omega > 0, alpha, beta, alpha + gamma
+// >= 0 and alpha + beta + gamma/2 < 1
+// standard errors come from the inverse of the numerical Hessian
+func FitGARCH(returns []float64, gjr bool) (*GARCH, error) {
+	if len(returns) < 10 {
+		panic(NOOR)
+	}
+	mu := Mean(returns)
+	e := make([]float64, len(returns))
+	for i, r := range returns {
+		e[i] = r - mu
+	}
+	v0 := SD(e)
+	v0 *= v0
+	if v0 == 0 {
+		return nil, errors.New(GARCHFAIL)
+	}
+	nll := func(p []float64) float64 {
+		ll, _ := garchLL(e, v0, p[0], p[1], p[2], p[3])
+		return -ll
+	}
+	// searched on omega scaled by the sample variance
+	scaled := func(x []float64) float64 {
+		g := 0.0
+		if gjr {
+			g = x[3]
+		}
+		return nll([]float64{x[0] * v0, x[1], x[2], g})
+	}
+	x0 := []float64{0.05, 0.05, 0.9}
+	step := []float64{0.02, 0.03, 0.05}
+	if gjr {
+		x0 = []float64{0.05, 0.03, 0.9, 0.04}
+		step = append(step, 0.03)
+	}
+	best, f := nelderMead(scaled, x0, step, 1e-12, 5000)
+	// restart from the optimum to escape a collapsed simplex
+	best, f = nelderMead(scaled, best, step, 1e-12, 5000)
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, errors.New(GARCHFAIL)
+	}
+	g := &GARCH{Mu: mu, Omega: best[0] * v0, Alpha: best[1], Beta: best[2], GJR: gjr}
+	if gjr {
+		g.Gamma = best[3]
+	}
+	p := []float64{g.Omega, g.Alpha, g.Beta, g.Gamma}
+	g.LogLik, g.Next = garchLL(e, v0, p[0], p[1], p[2], p[3])
+	k := len(x0)
+	g.StdErr = make([]float64, 4)
+	if cov, ok := invert(hessian(nll, p, k)); ok {
+		for i := 0; i < k; i++ {
+			g.StdErr[i] = math.Sqrt(math.Max(cov[i][i], 0))
+		}
+	} else {
+		for i := range g.StdErr {
+			g.StdErr[i] = math.NaN()
+		}
+	}
+	return g, nil
+}
+
+// garchLL Gaussian log likelihood of the residuals e and the variance
+// forecast for the following period
+// ll = -1/2 * SIGMA [ln(2pi) + ln(v^2(t)) + e^2(t) / v^2(t)]
+// returns -Inf outside the parameter constraints
+func garchLL(e []float64, v0, omega, alpha, beta, gamma float64) (float64, float64) {
+	if omega <= 0 || alpha < 0 || beta < 0 || alpha+gamma < 0 || alpha+beta+gamma/2 >= 1 {
+		return math.Inf(-1), math.NaN()
+	}
+	v2 := v0
+	var ll float64
+	for i, x := range e {
+		if i > 0 {
+			prev := e[i-1]
+			a := alpha
+			if prev < 0 {
+				a += gamma
+			}
+			v2 = omega + a*prev*prev + beta*v2
+		}
+		ll -= (math.Log(2*math.Pi) + math.Log(v2) + x*x/v2) / 2
+	}
+	last := e[len(e)-1]
+	a := alpha
+	if last < 0 {
+		a += gamma
+	}
+	return ll, omega + a*last*last + beta*v2
+}
+
+// hessian numerical Hessian of f over the first k coordinates of p by
+// central differences with steps relative to each coordinate
+func hessian(f func([]float64) float64, p []float64, k int) [][]float64 {
+	h := make([]float64, k)
+	for i := range h {
+		h[i] = 1e-4 * math.Max(math.Abs(p[i]), 1e-4)
+	}
+	at := func(i, j int, si, sj float64) float64 {
+		x := append([]float64(nil), p...)
+		x[i] += si * h[i]
+		x[j] += sj * h[j]
+		return f(x)
+	}
+	H := make([][]float64, k)
+	for i := range H {
+		H[i] = make([]float64, k)
+	}
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			v := (at(i, j, 1, 1) - at(i, j, 1, -1) - at(i, j, -1, 1) + at(i, j, -1, -1)) / (4 * h[i] * h[j])
+			H[i][j], H[j][i] = v, v
+		}
+	}
+	return H
+}
+
+// Persistence alpha + beta + gamma/2
+func (g *GARCH) Persistence() float64 {
+	return g.Alpha + g.Beta + g.Gamma/2
+}
+
+// LongRunVar unconditional variance per period
+// vl = omega / (1 - alpha - beta - gamma/2)
+func (g *GARCH) LongRunVar() float64 {
+	return g.Omega / (1 - g.Persistence())
+}
+
+// Forecast variance forecasts for periods 1 to h after the last return
+// v^2(T+i) = vl + p^(i-1) * (v^2(T+1) - vl)
+// vl = LongRunVar, p = Persistence
+func (g *GARCH) Forecast(h int) []float64 {
+	if h < 1 {
+		panic(NOOR)
+	}
+	vl, p := g.LongRunVar(), g.Persistence()
+	f := make([]float64, h)
+	for i := range f {
+		f[i] = vl + math.Pow(p, float64(i))*(g.Next-vl)
+	}
+	return f
+}
+
+// ForecastVol annualised volatility expected over the next h periods,
+// a forward looking v for BSM or VaR
+// v = (SIGMA Forecast(h) / h * n)^1/2
+// n = periods per year ex. TradingDays
+func (g *GARCH) ForecastVol(h int, n float64) float64 {
+	var sum float64
+	for _, v := range g.Forecast(h) {
+		sum += v
+	}
+	return math.Sqrt(sum / float64(h) * n)
+}
@@ -0,0 +1,190 @@
+package money
+
+/*
+The following types and functions are available
+
+Position holding of one security at cost
+QuoteSource supplies a Quote for a symbol
+  QuoteFunc adapts a function, LiveQuotes uses GetQuote
+Portfolio positions valued together
+Add adds to the position in the same symbol or opens one
+  (p *Portfolio) Add(pos Position)
+Value market valuation of every position with totals by currency
+  (p *Portfolio) Value(src QuoteSource) (*Valuation, error)
+*/
+
+import (
+	"errors"
+	"math"
+)
+
+// Position holding of one security
+// Quantity = shares held, may be fractional
+// CostBasis = total cost of the shares held
+// Currency = currency of the quote and cost ex. "USD"
+type Position struct {
+	Symbol    string
+	Exchange  string
+	Quantity  float64
+	CostBasis Money
+	Currency  string
+}
+
+// QuoteSource supplies the latest Quote of a symbol
+type QuoteSource interface {
+	Quote(symbol, exchange string) (*Quote, error)
+}
+
+// QuoteFunc is a function used as a QuoteSource, ex. a cache of quotes
+type QuoteFunc func(symbol, exchange string) (*Quote, error)
+
+// Quote calls f
+func (f QuoteFunc) Quote(symbol, exchange string) (*Quote, error) {
+	return f(symbol, exchange)
+}
+
+// LiveQuotes is a QuoteSource reading each quote with GetQuote
+type LiveQuotes struct{}
+
+// Quote reads a quote with GetQuote, its panics returned as errors
+func (LiveQuotes) Quote(symbol, exchange string) (q *Quote, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			q, err = nil, errors.New(QUOTEFAIL+exchange+" "+symbol)
+		}
+	}()
+	return new(Quote).GetQuote(symbol, exchange), nil
+}
+
+// Portfolio positions valued together
+// DividendFreq = dividend payments per year used to project income from
+// Quote.Dividend, 4 (quarterly) if zero
+type Portfolio struct {
+	Positions    []Position
+	DividendFreq float64
+}
+
+// Add adds pos to the position with the same Symbol and Exchange, adding
+// its Quantity and CostBasis, or opens a new position
+func (p *Portfolio) Add(pos Position) {
+	for i := range p.Positions {
+		h := &p.Positions[i]
+		if h.Symbol == pos.Symbol && h.Exchange == pos.Exchange {
+			h.Quantity += pos.Quantity
+			h.CostBasis.Add(&pos.CostBasis)
+			return
+		}
+	}
+	p.Positions = append(p.Positions, pos)
+}
+
+// Holding a Position valued at its latest quote
+// every ratio is a fraction ex. 0.10 for 10%, not a percent
+// Price = Quote.Price
+// MarketValue = Quantity * Price
+// Weight = MarketValue / total MarketValue of the positions in the same
+// Currency
+// Unrealised = MarketValue - CostBasis, the unrealised P&L
+// UnrealisedPct = Unrealised / CostBasis
+// DayChange = Quantity * Quote.Change
+// DayChangePct = Quote.ChangePct / 100, as Quote gives a percent
+// Income = projected annual dividends, Quantity * Quote.Dividend *
+// DividendFreq, or MarketValue * Quote.Yield / 100 if no Dividend is quoted
+// (Quote.Yield is a percent)
+type Holding struct {
+	Position
+	Price         Money
+	MarketValue   Money
+	Weight        float64
+	Unrealised    Money
+	UnrealisedPct float64
+	DayChange     Money
+	DayChangePct  float64
+	Income        Money
+}
+
+// Totals of the holdings in one currency, ratios as fractions as Holding
+// UnrealisedPct = Unrealised / CostBasis
+// DayChangePct = DayChange / (MarketValue - DayChange)
+// Yield = Income / MarketValue
+type Totals struct {
+	MarketValue   Money
+	CostBasis     Money
+	Unrealised    Money
+	UnrealisedPct float64
+	DayChange     Money
+	DayChangePct  float64
+	Income        Money
+	Yield         float64
+}
+
+// Valuation of a Portfolio
+// Holdings = each position in the order of Positions
+// Totals = totals by Currency, as values in different currencies are not
+// added together
+type Valuation struct {
+	Holdings []Holding
+	Totals   map[string]*Totals
+}
+
+// Value values every position at its latest quote from src
+// returns the first error of src
+func (p *Portfolio) Value(src QuoteSource) (*Valuation, error) {
+	freq := p.DividendFreq
+	if freq == 0 {
+		freq = 4
+	}
+	v := &Valuation{
+		Holdings: make([]Holding, len(p.Positions)),
+		Totals:   map[string]*Totals{},
+	}
+	for i, pos := range p.Positions {
+		q, err := src.Quote(pos.Symbol, pos.Exchange)
+		if err != nil {
+			return nil, err
+		}
+		h := &v.Holdings[i]
+		h.Position = pos
+		h.Price = q.Price
+		h.MarketValue.Setf(q.Price.Get() * pos.Quantity)
+		h.Unrealised = h.MarketValue
+		h.Unrealised.Sub(&pos.CostBasis)
+		h.UnrealisedPct = ratio(h.Unrealised.Get(), pos.CostBasis.Get())
+		h.DayChange.Setf(q.Change.Get() * pos.Quantity)
+		h.DayChangePct = q.ChangePct / 100
+		if q.Dividend.Value() != 0 {
+			h.Income.Setf(q.Dividend.Get() * pos.Quantity * freq)
+		} else {
+			h.Income.Setf(h.MarketValue.Get() * q.Yield / 100)
+		}
+		t, ok := v.Totals[pos.Currency]
+		if !ok {
+			t = new(Totals)
+			v.Totals[pos.Currency] = t
+		}
+		t.MarketValue.Add(&h.MarketValue)
+		t.CostBasis.Add(&h.CostBasis)
+		t.Unrealised.Add(&h.Unrealised)
+		t.DayChange.Add(&h.DayChange)
+		t.Income.Add(&h.Income)
+	}
+	for _, t := range v.Totals {
+		mv := t.MarketValue.Get()
+		t.UnrealisedPct = ratio(t.Unrealised.Get(), t.CostBasis.Get())
+		t.DayChangePct = ratio(t.DayChange.Get(), mv-t.DayChange.Get())
+		t.Yield = ratio(t.Income.Get(), mv)
+	}
+	for i := range v.Holdings {
+		h := &v.Holdings[i]
+		h.Weight = ratio(h.MarketValue.Get(), v.Totals[h.Currency].MarketValue.Get())
+	}
+	return v, nil
+}
+
+// ratio a / b, NaN when b is zero
+func ratio(a, b float64) float64 {
+	if b == 0 {
+		return math.NaN()
+	}
+	return a / b
+}
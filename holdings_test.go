@@ -0,0 +1,39 @@
+package money
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPortfolioValueFractions(t *testing.T) {
+	var cost Money
+	cost.Setf(1000)
+	p := Portfolio{Positions: []Position{{Symbol: "ABC", Quantity: 10, CostBasis: cost, Currency: "USD"}}}
+	src := QuoteFunc(func(symbol, exchange string) (*Quote, error) {
+		q := &Quote{ChangePct: 10, Yield: 2}
+		q.Price.Setf(110)
+		q.Change.Setf(10)
+		return q, nil
+	})
+	v, err := p.Value(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, tot := v.Holdings[0], v.Totals["USD"]
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"Holding UnrealisedPct", h.UnrealisedPct, 0.1},
+		{"Holding DayChangePct", h.DayChangePct, 0.1},
+		{"Holding Weight", h.Weight, 1},
+		{"Totals UnrealisedPct", tot.UnrealisedPct, 0.1},
+		{"Totals DayChangePct", tot.DayChangePct, 0.1},
+		{"Totals Yield", tot.Yield, 0.02},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-12 {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
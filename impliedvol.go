@@ -0,0 +1,120 @@
+package money
+
+/*
+The following functions are available
+
+ImpliedVol Black-Scholes-Merton implied volatility from an option premium
+  ImpliedVol(price, s, k, t, r, q float64, o OptionType) (float64, error)
+*/
+
+import "math"
+
+const (
+	ivMaxVol = 10.0 // 1000% volatility upper bracket
+	ivTol    = 1e-12
+)
+
+// ImpliedVol Black-Scholes-Merton implied volatility
+// solves BSM(s, k, t, r, q, v, o) = price for v
+// price = option premium
+// s, k, t, r, q = as for BSM
+// lower bound call = max(s * e^(-qt) - k * e^(-rt), 0)
+// lower bound put = max(k * e^(-rt) - s * e^(-qt), 0)
+// upper bound call = s * e^(-qt), put = k * e^(-rt)
+// returns ErrIVLow or ErrIVHigh for prices outside the no-arbitrage
+// bounds and zero volatility for a price equal to the lower bound
+// an in the money option is turned into its out of the money counterpart
+// by put-call parity, then ln(premium) is solved by a Newton iteration
+// safeguarded by bisection, started from the Corrado-Miller estimate (or
+// the maximum vega point for options far from the money)
+func ImpliedVol(price, s, k, t, r, q float64, o OptionType) (float64, error) {
+	if s <= 0 || k <= 0 || t <= 0 {
+		panic(NOOR)
+	}
+	if o != Call && o != Put {
+		panic(NOOR)
+	}
+	sq := s * math.Exp(-q*t)
+	kr := k * math.Exp(-r*t)
+	lower, upper := intrinsic(sq, kr, o), sq
+	if o == Put {
+		upper = kr
+	}
+	if math.IsNaN(price) || price < lower-ivTol*math.Max(1, lower) {
+		return math.NaN(), ErrIVLow
+	}
+	if price >= upper {
+		return math.NaN(), ErrIVHigh
+	}
+	otm, c := Call, price-lower
+	if sq > kr {
+		otm = Put
+	}
+	if c <= 0 {
+		return 0, nil
+	}
+	lnc := math.Log(c)
+	sqt := math.Sqrt(t)
+	lo, hi := 0.0, ivMaxVol
+	if otmPrice(sq, kr, sqt, hi, otm) < c {
+		return math.NaN(), ErrIVFail
+	}
+	v := ivGuess(c, sq, kr, t, otm)
+	for i := 0; i < 100; i++ {
+		if v <= lo || v >= hi {
+			v = (lo + hi) / 2
+		}
+		p := otmPrice(sq, kr, sqt, v, otm)
+		diff := math.Log(p) - lnc
+		if math.Abs(diff) <= ivTol || hi-lo <= ivTol*v {
+			return v, nil
+		}
+		if diff > 0 {
+			hi = v
+		} else {
+			lo = v
+		}
+		d1 := math.Log(sq/kr)/(v*sqt) + v*sqt/2
+		next := v - diff*p/(sq*ND(d1)*sqt)
+		if p <= 0 || math.IsNaN(next) || next <= lo || next >= hi {
+			next = (lo + hi) / 2
+		}
+		v = next
+	}
+	return math.NaN(), ErrIVFail
+}
+
+// otmPrice BSM premium on the forward terms sq = s * e^(-qt), kr = k * e^(-rt)
+func otmPrice(sq, kr, sqt, v float64, o OptionType) float64 {
+	vt := v * sqt
+	d1 := math.Log(sq/kr)/vt + vt/2
+	d2 := d1 - vt
+	if o == Call {
+		return sq*CND(d1) - kr*CND(d2)
+	}
+	return kr*CND(-d2) - sq*CND(-d1)
+}
+
+// ivGuess initial volatility for ImpliedVol
+// Corrado-Miller
+// v * t^1/2 = (2pi)^1/2 / (sq + kr) * [c - (sq - kr)/2 + ((c - (sq - kr)/2)^2 - (sq - kr)^2/pi)^1/2]
+// falling back to v = (2 * |ln(sq/kr)| / t)^1/2, where vega is greatest
+func ivGuess(price, sq, kr, t float64, o OptionType) float64 {
+	c := price
+	if o == Put {
+		c = price + sq - kr
+	}
+	h := c - (sq-kr)/2
+	disc := h*h - (sq-kr)*(sq-kr)/math.Pi
+	if disc >= 0 {
+		v := math.Sqrt(2*math.Pi) / (sq + kr) * (h + math.Sqrt(disc)) / math.Sqrt(t)
+		if v > 0 && v < ivMaxVol {
+			return v
+		}
+	}
+	v := math.Sqrt(2 * math.Abs(math.Log(sq/kr)) / t)
+	if v == 0 || v >= ivMaxVol {
+		v = 0.2
+	}
+	return v
+}
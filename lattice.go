@@ -0,0 +1,204 @@
+package money
+
+/*
+The following types and functions are available
+
+TreeMethod lattice construction (CRR, LeisenReimer, Trinomial)
+Exercise exercise style (European, American, Bermudan)
+Dividend discrete cash dividend paid at a time in years
+Tree lattice pricer settings
+  Tree{Method, Steps, Exercise, Dates, Dividends}
+Price option premium on the lattice
+  (tr *Tree) Price(s, k, t, r, q, v float64, o OptionType) float64
+*/
+
+import (
+	"math"
+	"sort"
+)
+
+// TreeMethod is the lattice used by Tree
+type TreeMethod int
+
+const (
+	CRR          TreeMethod = iota // Cox-Ross-Rubinstein binomial
+	LeisenReimer                   // Leisen-Reimer binomial (Peizer-Pratt inversion)
+	Trinomial                      // Boyle trinomial
+)
+
+// Exercise is when an option may be exercised
+type Exercise int
+
+const (
+	European Exercise = iota // at expiry only
+	American                 // at any time
+	Bermudan                 // on Tree.Dates and at expiry
+)
+
+// Dividend is a discrete cash dividend
+type Dividend struct {
+	T      float64 // time of payment in years
+	Amount float64 // cash amount per share
+}
+
+// Tree lattice option pricer
+// Method = lattice construction
+// Steps = number of time steps, 100 if zero (Leisen-Reimer rounds up to odd)
+// Exercise = European, American or Bermudan
+// Dates = Bermudan exercise times in years, moved to the nearest step
+// Dividends = discrete cash dividends before expiry, handled by the
+// escrowed dividend model: the lattice is built on s less the present
+// value of the dividends, which is added back to the node price when
+// testing early exercise
+type Tree struct {
+	Method    TreeMethod
+	Steps     int
+	Exercise  Exercise
+	Dates     []float64
+	Dividends []Dividend
+}
+
+// Price option premium on the lattice
+// s = Spot price
+// k = strike price
+// t = time to expire in years
+// r = risk free rate (continuous)
+// q = dividend yield (continuous)
+// v = volatility (sigma)
+// o = Call or Put
+// a European tree converges on BSM as Steps increase, with v = 0 it is
+// the discounted intrinsic value on the forward as BSM
+func (tr *Tree) Price(s, k, t, r, q, v float64, o OptionType) float64 {
+	if s <= 0 || k <= 0 || t < 0 || v < 0 {
+		panic(NOOR)
+	}
+	if o != Call && o != Put {
+		panic(NOOR)
+	}
+	if t == 0 {
+		return intrinsic(s, k, o)
+	}
+	n := tr.Steps
+	if n <= 0 {
+		n = 100
+	}
+	if tr.Method == LeisenReimer && n%2 == 0 {
+		n++
+	}
+	dt := t / float64(n)
+	escrow := func(at float64) float64 {
+		var pv float64
+		for _, d := range tr.Dividends {
+			if d.T > at && d.T <= t {
+				pv += d.Amount * math.Exp(-r*(d.T-at))
+			}
+		}
+		return pv
+	}
+	ss := s - escrow(0)
+	if ss <= 0 {
+		panic(NOOR)
+	}
+	exercise := tr.exerciseSteps(n, dt)
+	if v == 0 { // the price path is certain, exercise at its best step
+		value := math.Exp(-r*t) * intrinsic(ss*math.Exp((r-q)*t), k, o)
+		for i, ok := range exercise {
+			if ok {
+				at := float64(i) * dt
+				st := ss*math.Exp((r-q)*at) + escrow(at)
+				value = math.Max(value, math.Exp(-r*at)*intrinsic(st, k, o))
+			}
+		}
+		return value
+	}
+	disc := math.Exp(-r * dt)
+	switch tr.Method {
+	case CRR, LeisenReimer:
+		var u, d, p float64
+		if tr.Method == CRR {
+			u = math.Exp(v * math.Sqrt(dt))
+			d = 1 / u
+			p = (math.Exp((r-q)*dt) - d) / (u - d)
+		} else {
+			vt := v * math.Sqrt(t)
+			d1 := (math.Log(ss/k) + (r-q+v*v/2)*t) / vt
+			d2 := d1 - vt
+			p = peizerPratt(d2, n)
+			growth := math.Exp((r - q) * dt)
+			u = growth * peizerPratt(d1, n) / p
+			d = (growth - p*u) / (1 - p)
+		}
+		vals := make([]float64, n+1)
+		for j := 0; j <= n; j++ {
+			vals[j] = intrinsic(ss*math.Pow(u, float64(j))*math.Pow(d, float64(n-j)), k, o)
+		}
+		for i := n - 1; i >= 0; i-- {
+			pv := escrow(float64(i) * dt)
+			for j := 0; j <= i; j++ {
+				vals[j] = disc * (p*vals[j+1] + (1-p)*vals[j])
+				if exercise[i] {
+					st := ss*math.Pow(u, float64(j))*math.Pow(d, float64(i-j)) + pv
+					vals[j] = math.Max(vals[j], intrinsic(st, k, o))
+				}
+			}
+		}
+		return vals[0]
+	case Trinomial:
+		dx := v * math.Sqrt(2*dt)
+		eu := math.Exp(v * math.Sqrt(dt/2))
+		ed := 1 / eu
+		g := math.Exp((r - q) * dt / 2)
+		pu := math.Pow((g-ed)/(eu-ed), 2)
+		pd := math.Pow((eu-g)/(eu-ed), 2)
+		pm := 1 - pu - pd
+		vals := make([]float64, 2*n+1)
+		for j := 0; j <= 2*n; j++ {
+			vals[j] = intrinsic(ss*math.Exp(float64(j-n)*dx), k, o)
+		}
+		for i := n - 1; i >= 0; i-- {
+			pv := escrow(float64(i) * dt)
+			for j := 0; j <= 2*i; j++ {
+				vals[j] = disc * (pu*vals[j+2] + pm*vals[j+1] + pd*vals[j])
+				if exercise[i] {
+					st := ss*math.Exp(float64(j-i)*dx) + pv
+					vals[j] = math.Max(vals[j], intrinsic(st, k, o))
+				}
+			}
+		}
+		return vals[0]
+	}
+	panic(NOOR)
+}
+
+// exerciseSteps marks the steps before expiry at which exercise is allowed
+func (tr *Tree) exerciseSteps(n int, dt float64) []bool {
+	ex := make([]bool, n)
+	switch tr.Exercise {
+	case American:
+		for i := range ex {
+			ex[i] = true
+		}
+	case Bermudan:
+		dates := append([]float64(nil), tr.Dates...)
+		sort.Float64s(dates)
+		for _, d := range dates {
+			i := int(math.Round(d / dt))
+			if i >= 0 && i < n {
+				ex[i] = true
+			}
+		}
+	}
+	return ex
+}
+
+// peizerPratt Peizer-Pratt method 2 inversion of the normal distribution
+// h(z) = 1/2 + sign(z) * 1/2 * (1 - e^(-(z / (n + 1/3 + 0.1/(n+1)))^2 * (n + 1/6)))^1/2
+func peizerPratt(z float64, n int) float64 {
+	nf := float64(n)
+	x := z / (nf + 1.0/3 + 0.1/(nf+1))
+	h := 0.5 * math.Sqrt(1-math.Exp(-x*x*(nf+1.0/6)))
+	if z < 0 {
+		return 0.5 - h
+	}
+	return 0.5 + h
+}
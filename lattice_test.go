@@ -0,0 +1,81 @@
+package money
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTreeEuropean(t *testing.T) {
+	// European trees converge on BSM
+	tests := []struct {
+		method TreeMethod
+		steps  int
+		tol    float64
+	}{
+		{CRR, 2000, 2e-3},
+		{LeisenReimer, 201, 1e-4},
+		{Trinomial, 1000, 2e-3},
+	}
+	for _, tt := range tests {
+		tr := Tree{Method: tt.method, Steps: tt.steps}
+		for _, o := range []OptionType{Call, Put} {
+			got := tr.Price(42, 40, 0.5, 0.1, 0.03, 0.2, o)
+			want := BSM(42, 40, 0.5, 0.1, 0.03, 0.2, o)
+			if math.Abs(got-want) > tt.tol {
+				t.Errorf("method %v %v = %v, BSM %v", tt.method, o, got, want)
+			}
+		}
+	}
+}
+
+func TestTreeAmerican(t *testing.T) {
+	for _, m := range []TreeMethod{CRR, LeisenReimer, Trinomial} {
+		eu := Tree{Method: m, Steps: 500}
+		am := Tree{Method: m, Steps: 500, Exercise: American}
+		be := Tree{Method: m, Steps: 500, Exercise: Bermudan, Dates: []float64{0.25, 0.5, 0.75}}
+		put := am.Price(40, 44, 1, 0.08, 0, 0.25, Put)
+		bput := be.Price(40, 44, 1, 0.08, 0, 0.25, Put)
+		eput := eu.Price(40, 44, 1, 0.08, 0, 0.25, Put)
+		if !(put > bput && bput > eput) {
+			t.Errorf("method %v put American %v, Bermudan %v, European %v", m, put, bput, eput)
+		}
+		// early exercise of a call on a stock paying no dividend is never optimal
+		call := am.Price(40, 44, 1, 0.08, 0, 0.25, Call)
+		if ecall := eu.Price(40, 44, 1, 0.08, 0, 0.25, Call); math.Abs(call-ecall) > 1e-9 {
+			t.Errorf("method %v call American %v, European %v", m, call, ecall)
+		}
+	}
+	// the methods agree on the American put
+	ref := (&Tree{Method: LeisenReimer, Steps: 1001, Exercise: American}).Price(40, 44, 1, 0.08, 0, 0.25, Put)
+	for _, m := range []TreeMethod{CRR, Trinomial} {
+		tr := Tree{Method: m, Steps: 2000, Exercise: American}
+		if got := tr.Price(40, 44, 1, 0.08, 0, 0.25, Put); math.Abs(got-ref) > 5e-3 {
+			t.Errorf("method %v American put %v, Leisen-Reimer %v", m, got, ref)
+		}
+	}
+}
+
+func TestTreeZeroVolatility(t *testing.T) {
+	tests := []struct {
+		name string
+		tr   Tree
+		o    OptionType
+		want float64
+	}{
+		{"European call", Tree{}, Call, 100 - 90*math.Exp(-0.05)},
+		{"European put", Tree{}, Put, 0},
+		{"American put", Tree{Exercise: American}, Put, 0},
+		{"Bermudan call", Tree{Method: Trinomial, Exercise: Bermudan, Dates: []float64{0.5}}, Call, 100 - 90*math.Exp(-0.05)},
+	}
+	for _, tt := range tests {
+		got := tt.tr.Price(100, 90, 1, 0.05, 0, 0, tt.o)
+		if math.IsNaN(got) || math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	// an in the money put worth more exercised now than on the forward
+	tr := Tree{Method: LeisenReimer, Exercise: American}
+	if got := tr.Price(80, 100, 1, 0.05, 0, 0, Put); math.Abs(got-20) > 1e-12 {
+		t.Errorf("American in the money put = %v, want 20", got)
+	}
+}
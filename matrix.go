@@ -0,0 +1,77 @@
+package money
+
+import "math"
+
+// invert returns the inverse of the square matrix a by Gauss-Jordan
+// elimination with partial pivoting, false if a is singular
+func invert(a [][]float64) ([][]float64, bool) {
+	n := len(a)
+	m := make([][]float64, n)
+	inv := make([][]float64, n)
+	for i := range a {
+		if len(a[i]) != n {
+			panic(NOOR)
+		}
+		m[i] = append([]float64(nil), a[i]...)
+		inv[i] = make([]float64, n)
+		inv[i][i] = 1
+	}
+	for c := 0; c < n; c++ {
+		p := c
+		for r := c + 1; r < n; r++ {
+			if math.Abs(m[r][c]) > math.Abs(m[p][c]) {
+				p = r
+			}
+		}
+		if math.Abs(m[p][c]) < 1e-300 {
+			return nil, false
+		}
+		m[c], m[p] = m[p], m[c]
+		inv[c], inv[p] = inv[p], inv[c]
+		d := m[c][c]
+		for j := 0; j < n; j++ {
+			m[c][j] /= d
+			inv[c][j] /= d
+		}
+		for r := 0; r < n; r++ {
+			if r == c || m[r][c] == 0 {
+				continue
+			}
+			f := m[r][c]
+			for j := 0; j < n; j++ {
+				m[r][j] -= f * m[c][j]
+				inv[r][j] -= f * inv[c][j]
+			}
+		}
+	}
+	return inv, true
+}
+
+// cholesky returns the lower triangular l with l * l' = a for a symmetric
+// positive semi-definite matrix, columns with no remaining variance are
+// left zero
+func cholesky(a [][]float64) [][]float64 {
+	n := len(a)
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+	}
+	for j := 0; j < n; j++ {
+		d := a[j][j]
+		for k := 0; k < j; k++ {
+			d -= l[j][k] * l[j][k]
+		}
+		if d <= 1e-14*math.Abs(a[j][j]) {
+			continue
+		}
+		l[j][j] = math.Sqrt(d)
+		for i := j + 1; i < n; i++ {
+			s := a[i][j]
+			for k := 0; k < j; k++ {
+				s -= l[i][k] * l[j][k]
+			}
+			l[i][j] = s / l[j][j]
+		}
+	}
+	return l
+}
@@ -0,0 +1,349 @@
+package money
+
+/*
+The following types and functions are available
+
+PathGenerator builds a price path from standard normal draws
+  GBM geometric Brownian motion
+Payoff values a single price path at expiry
+  PayoffFunc, Vanilla, Asian, Lookback, BarrierOption
+MonteCarlo simulation settings
+  MonteCarlo{Paths, Seed, Antithetic, Workers, Control, ControlMean}
+Price discounted expected payoff with its standard error
+  (mc *MonteCarlo) Price(gen PathGenerator, pay Payoff, df float64) MCResult
+*/
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// mcBlock is the number of draws simulated from one seeded source, blocks
+// are combined in order so results do not depend on the number of workers
+const mcBlock = 1024
+
+// PathGenerator builds a price path from standard normal draws
+// Steps = number of time steps, the path holds Steps+1 prices
+// Path fills path (len Steps+1) from z (len Steps), it must only write to
+// path as it is called from several goroutines at once
+type PathGenerator interface {
+	Steps() int
+	Path(z, path []float64)
+}
+
+// GBM geometric Brownian motion
+// s(t+dt) = s(t) * e^((r - q - v^2/2)dt + v * dt^1/2 * z)
+// S = Spot price
+// T = time to expire in years
+// R = risk free rate
+// Q = dividend yield
+// V = volatility (sigma)
+// N = number of time steps
+type GBM struct {
+	S, T, R, Q, V float64
+	N             int
+}
+
+// Steps number of time steps
+func (g GBM) Steps() int {
+	if g.N <= 0 {
+		return 1
+	}
+	return g.N
+}
+
+// Path GBM price path
+func (g GBM) Path(z, path []float64) {
+	dt := g.T / float64(len(z))
+	drift := (g.R - g.Q - g.V*g.V/2) * dt
+	vol := g.V * math.Sqrt(dt)
+	path[0] = g.S
+	for i, x := range z {
+		path[i+1] = path[i] * math.Exp(drift+vol*x)
+	}
+}
+
+// Payoff is the undiscounted value of a price path at expiry
+type Payoff interface {
+	Payoff(path []float64) float64
+}
+
+// PayoffFunc adapts a function to the Payoff interface
+type PayoffFunc func(path []float64) float64
+
+// Payoff calls f(path)
+func (f PayoffFunc) Payoff(path []float64) float64 {
+	return f(path)
+}
+
+// Vanilla European option on the final price
+type Vanilla struct {
+	K    float64
+	Type OptionType
+}
+
+// Payoff max(s(T) - k, 0) for a call, max(k - s(T), 0) for a put
+func (p Vanilla) Payoff(path []float64) float64 {
+	return intrinsic(path[len(path)-1], p.K, p.Type)
+}
+
+// Asian fixed strike option on the average of the path after the spot
+// Geometric = geometric rather than arithmetic average
+type Asian struct {
+	K         float64
+	Type      OptionType
+	Geometric bool
+}
+
+// Payoff max(avg - k, 0) for a call, max(k - avg, 0) for a put
+func (p Asian) Payoff(path []float64) float64 {
+	var sum float64
+	for _, s := range path[1:] {
+		if p.Geometric {
+			sum += math.Log(s)
+		} else {
+			sum += s
+		}
+	}
+	avg := sum / float64(len(path)-1)
+	if p.Geometric {
+		avg = math.Exp(avg)
+	}
+	return intrinsic(avg, p.K, p.Type)
+}
+
+// Lookback option on the path maximum or minimum
+// Floating = floating strike: call s(T) - min, put max - s(T)
+// otherwise fixed strike K: call max(max - k, 0), put max(k - min, 0)
+type Lookback struct {
+	K        float64
+	Type     OptionType
+	Floating bool
+}
+
+// Payoff lookback payoff
+func (p Lookback) Payoff(path []float64) float64 {
+	lo, hi := path[0], path[0]
+	for _, s := range path {
+		lo = math.Min(lo, s)
+		hi = math.Max(hi, s)
+	}
+	last := path[len(path)-1]
+	switch {
+	case p.Floating && p.Type == Call:
+		return last - lo
+	case p.Floating:
+		return hi - last
+	case p.Type == Call:
+		return math.Max(hi-p.K, 0)
+	}
+	return math.Max(p.K-lo, 0)
+}
+
+// Barrier is the direction and effect of an option barrier
+type Barrier int
+
+const (
+	DownIn  Barrier = iota // comes alive when s falls to H
+	DownOut                // dies when s falls to H
+	UpIn                   // comes alive when s rises to H
+	UpOut                  // dies when s rises to H
+)
+
+// BarrierOption knock-in or knock-out option monitored at each path step
+// H = barrier level
+// Rebate = cash paid at expiry if the option is knocked out or never
+// knocked in
+type BarrierOption struct {
+	K, H, Rebate float64
+	Type         OptionType
+	Barrier      Barrier
+}
+
+// Payoff barrier payoff
+func (p BarrierOption) Payoff(path []float64) float64 {
+	hit := false
+	for _, s := range path {
+		if (p.Barrier == DownIn || p.Barrier == DownOut) && s <= p.H ||
+			(p.Barrier == UpIn || p.Barrier == UpOut) && s >= p.H {
+			hit = true
+			break
+		}
+	}
+	in := p.Barrier == DownIn || p.Barrier == UpIn
+	if hit == in {
+		return intrinsic(path[len(path)-1], p.K, p.Type)
+	}
+	return p.Rebate
+}
+
+// MonteCarlo simulation settings
+// Paths = number of simulated paths (antithetic pairs count as two)
+// Seed = seed of the random number generator, equal seeds give equal results
+// Antithetic = also simulate each path with the normal draws negated
+// Workers = goroutines generating paths, GOMAXPROCS if zero
+// Control = control variate payoff simulated on the same paths (optional)
+// ControlMean = known discounted expected value of Control
+type MonteCarlo struct {
+	Paths       int
+	Seed        int64
+	Antithetic  bool
+	Workers     int
+	Control     Payoff
+	ControlMean float64
+}
+
+// MCResult is a Monte Carlo estimate
+// Price = discounted mean payoff
+// StdErr = standard error of Price
+// Paths = number of paths simulated
+type MCResult struct {
+	Price, StdErr float64
+	Paths         int
+}
+
+// mcSums running sums of the discounted payoff x and control y
+type mcSums struct {
+	n                float64
+	x, y, xx, yy, xy float64
+}
+
+// Price discounted expected payoff
+// price = df * SIGMA payoff(path) / n
+// with a control variate c of known mean E[c]
+// price = SIGMA (x - b * (c - E[c])) / n, b = Cov(x, c) / Var(c)
+// gen = path generator
+// pay = payoff
+// df = discount factor from expiry to today ex. e^(-rt)
+// draws are split into fixed blocks, each with its own source seeded from
+// Seed, so results repeat exactly whatever the number of Workers
+func (mc *MonteCarlo) Price(gen PathGenerator, pay Payoff, df float64) MCResult {
+	if mc.Paths <= 0 || gen == nil || pay == nil {
+		panic(NOOR)
+	}
+	draws := mc.Paths
+	if mc.Antithetic {
+		draws = (draws + 1) / 2
+	}
+	sums := make([]mcSums, (draws+mcBlock-1)/mcBlock)
+	mc.blocks(draws, func() func(b int, rng *rand.Rand, n int) {
+		steps := gen.Steps()
+		z := make([]float64, steps)
+		path := make([]float64, steps+1)
+		return func(b int, rng *rand.Rand, n int) {
+			sums[b] = mc.block(rng, gen, pay, df, n, z, path)
+		}
+	})
+	var t mcSums
+	for _, s := range sums {
+		t.n += s.n
+		t.x += s.x
+		t.y += s.y
+		t.xx += s.xx
+		t.yy += s.yy
+		t.xy += s.xy
+	}
+	paths := draws
+	if mc.Antithetic {
+		paths *= 2
+	}
+	mean := t.x / t.n
+	variance := (t.xx - t.n*mean*mean) / (t.n - 1)
+	if mc.Control != nil {
+		ym := t.y / t.n
+		vy := (t.yy - t.n*ym*ym) / (t.n - 1)
+		cxy := (t.xy - t.n*mean*ym) / (t.n - 1)
+		if vy > 0 {
+			b := cxy / vy
+			mean -= b * (ym - mc.ControlMean)
+			variance -= cxy * cxy / vy
+		}
+	}
+	if t.n < 2 || variance < 0 {
+		variance = 0
+	}
+	return MCResult{Price: mean, StdErr: math.Sqrt(variance / t.n), Paths: paths}
+}
+
+// blocks splits draws into fixed blocks of mcBlock, each simulated from
+// its own source seeded by blockSeed, on Workers goroutines
+// worker is called once per goroutine and returns the function simulating
+// the n draws of block b from rng, so it can keep its own buffers
+// results repeat exactly whatever the number of Workers
+func (mc *MonteCarlo) blocks(draws int, worker func() func(b int, rng *rand.Rand, n int)) {
+	blocks := (draws + mcBlock - 1) / mcBlock
+	workers := mc.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			run := worker()
+			for b := range next {
+				n := mcBlock
+				if b == blocks-1 {
+					n = draws - b*mcBlock
+				}
+				run(b, rand.New(rand.NewSource(blockSeed(mc.Seed, b))), n)
+			}
+		}()
+	}
+	for b := 0; b < blocks; b++ {
+		next <- b
+	}
+	close(next)
+	wg.Wait()
+}
+
+// blockSeed seed of block b, mixed (splitmix64) so that the blocks of
+// nearby seeds share no sources
+func blockSeed(seed int64, b int) int64 {
+	mix := func(x uint64) uint64 {
+		x += 0x9e3779b97f4a7c15
+		x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+		x = (x ^ x>>27) * 0x94d049bb133111eb
+		return x ^ x>>31
+	}
+	return int64(mix(mix(uint64(seed)) + uint64(b)))
+}
+
+// block simulates n draws from rng
+func (mc *MonteCarlo) block(rng *rand.Rand, gen PathGenerator, pay Payoff, df float64, n int, z, path []float64) mcSums {
+	var s mcSums
+	for i := 0; i < n; i++ {
+		for j := range z {
+			z[j] = rng.NormFloat64()
+		}
+		x, y := mc.sample(gen, pay, df, z, path)
+		if mc.Antithetic {
+			for j := range z {
+				z[j] = -z[j]
+			}
+			xa, ya := mc.sample(gen, pay, df, z, path)
+			x, y = (x+xa)/2, (y+ya)/2
+		}
+		s.n++
+		s.x += x
+		s.y += y
+		s.xx += x * x
+		s.yy += y * y
+		s.xy += x * y
+	}
+	return s
+}
+
+// sample discounted payoff and control value of one path
+func (mc *MonteCarlo) sample(gen PathGenerator, pay Payoff, df float64, z, path []float64) (x, y float64) {
+	gen.Path(z, path)
+	x = df * pay.Payoff(path)
+	if mc.Control != nil {
+		y = df * mc.Control.Payoff(path)
+	}
+	return x, y
+}
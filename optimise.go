@@ -0,0 +1,315 @@
+package money
+
+/*
+The following types and functions are available
+
+Allocation portfolio weights with their return, risk and risk contributions
+MeanVariance mean-variance optimiser with bounds and group constraints
+  MeanVariance{Mean, Cov, Min, Max, Groups}
+MinVariance portfolio of least risk
+  (mv *MeanVariance) MinVariance() (*Allocation, error)
+MaxSharpe portfolio of greatest Sharpe ratio (tangency portfolio)
+  (mv *MeanVariance) MaxSharpe(rf float64) (*Allocation, error)
+TargetReturn portfolio of least risk with a given return
+  (mv *MeanVariance) TargetReturn(r float64) (*Allocation, error)
+Frontier efficient frontier from the least risk to the greatest return
+  (mv *MeanVariance) Frontier(n int) ([]Allocation, error)
+*/
+
+import (
+	"errors"
+	"math"
+)
+
+// Allocation weights of a portfolio
+// Return = SIGMA w[i] * mean[i] (0 without means)
+// Risk = sqrt(w' * cov * w), the volatility
+// RiskContrib = w[i] * (cov * w)[i] / Risk^2, each asset's share of the
+// variance, summing to 1
+type Allocation struct {
+	Weights      []float64
+	Return, Risk float64
+	RiskContrib  []float64
+}
+
+// newAllocation fills in the return, risk and risk contributions of w
+func newAllocation(w, mean []float64, cov [][]float64) *Allocation {
+	al := &Allocation{Weights: w, RiskContrib: make([]float64, len(w))}
+	for i := range mean {
+		al.Return += w[i] * mean[i]
+	}
+	var v float64
+	for i := range w {
+		var cw float64
+		for j := range w {
+			cw += cov[i][j] * w[j]
+		}
+		al.RiskContrib[i] = w[i] * cw
+		v += w[i] * cw
+	}
+	for i := range al.RiskContrib {
+		al.RiskContrib[i] /= v
+	}
+	al.Risk = math.Sqrt(v)
+	return al
+}
+
+// Group limits the total weight of a set of assets ex. a sector
+// Assets = indexes into Mean and Cov
+type Group struct {
+	Assets   []int
+	Min, Max float64
+}
+
+// MeanVariance Markowitz portfolio optimisation, weights summing to 1
+// Mean = expected return of each asset (per period or annual)
+// Cov = covariance matrix of the same periods ex. CovMatrix
+// Min, Max = bounds on each weight, nil for long only (0 and 1), use
+// math.Inf for no bound
+// Groups = limits on the total weight of sets of assets
+// solved as quadratic programmes (see quadprog)
+type MeanVariance struct {
+	Mean     []float64
+	Cov      [][]float64
+	Min, Max []float64
+	Groups   []Group
+}
+
+// constraints rows of the weight constraints, budget first
+func (mv *MeanVariance) constraints() (a [][]float64, l, u []float64) {
+	n := len(mv.Cov)
+	if n == 0 || (mv.Mean != nil && len(mv.Mean) != n) ||
+		(mv.Min != nil && len(mv.Min) != n) || (mv.Max != nil && len(mv.Max) != n) {
+		panic(NOOR)
+	}
+	row := func(lo, hi float64, assets ...int) {
+		r := make([]float64, n)
+		for _, i := range assets {
+			if i < 0 || i >= n {
+				panic(NOOR)
+			}
+			r[i] = 1
+		}
+		a = append(a, r)
+		l = append(l, lo)
+		u = append(u, hi)
+	}
+	all := make([]int, n)
+	for i := range all {
+		all[i] = i
+	}
+	row(1, 1, all...)
+	for i := 0; i < n; i++ {
+		lo, hi := mv.bounds(i)
+		row(lo, hi, i)
+	}
+	for _, g := range mv.Groups {
+		row(g.Min, g.Max, g.Assets...)
+	}
+	return a, l, u
+}
+
+// bounds of weight i
+func (mv *MeanVariance) bounds(i int) (lo, hi float64) {
+	lo, hi = 0, 1
+	if mv.Min != nil {
+		lo = mv.Min[i]
+	}
+	if mv.Max != nil {
+		hi = mv.Max[i]
+	}
+	return lo, hi
+}
+
+// scaledCov covariance divided by its mean variance, so that the solver
+// tolerances suit daily or annual data alike
+func (mv *MeanVariance) scaledCov() [][]float64 {
+	n := len(mv.Cov)
+	var s float64
+	for i := 0; i < n; i++ {
+		s += mv.Cov[i][i]
+	}
+	s /= float64(n)
+	if s <= 0 {
+		s = 1
+	}
+	p := make([][]float64, n)
+	for i := range p {
+		p[i] = make([]float64, n)
+		for j := range p[i] {
+			p[i][j] = mv.Cov[i][j] / s
+		}
+	}
+	return p
+}
+
+// solve minimises w' * p * w / 2 + q' * w subject to l <= a * w <= u
+func (mv *MeanVariance) solve(p [][]float64, q []float64, a [][]float64, l, u []float64) (*Allocation, error) {
+	w, ok := quadprog(p, q, a, l, u)
+	if !ok {
+		return nil, errors.New(MVFAIL)
+	}
+	return newAllocation(w, mv.Mean, mv.Cov), nil
+}
+
+// MinVariance portfolio of least risk
+// min w' * cov * w subject to the constraints
+func (mv *MeanVariance) MinVariance() (*Allocation, error) {
+	a, l, u := mv.constraints()
+	return mv.solve(mv.scaledCov(), make([]float64, len(mv.Cov)), a, l, u)
+}
+
+// TargetReturn portfolio of least risk with return r
+// min w' * cov * w subject to SIGMA w[i] * mean[i] = r and the constraints
+func (mv *MeanVariance) TargetReturn(r float64) (*Allocation, error) {
+	if mv.Mean == nil {
+		panic(NOOR)
+	}
+	a, l, u := mv.constraints()
+	a = append(a, mv.Mean)
+	l = append(l, r)
+	u = append(u, r)
+	return mv.solve(mv.scaledCov(), make([]float64, len(mv.Cov)), a, l, u)
+}
+
+// maxReturn portfolio of greatest return, the least risk among ties
+func (mv *MeanVariance) maxReturn() (*Allocation, error) {
+	if mv.Mean == nil {
+		panic(NOOR)
+	}
+	a, l, u := mv.constraints()
+	p := mv.scaledCov()
+	var s float64
+	for _, m := range mv.Mean {
+		s = math.Max(s, math.Abs(m))
+	}
+	if s == 0 {
+		s = 1
+	}
+	q := make([]float64, len(mv.Mean))
+	for i := range q {
+		q[i] = -mv.Mean[i] / s
+		for j := range p[i] {
+			p[i][j] *= 1e-6
+		}
+	}
+	al, err := mv.solve(p, q, a, l, u)
+	if err != nil {
+		return nil, err
+	}
+	return mv.TargetReturn(al.Return)
+}
+
+// MaxSharpe portfolio of greatest Sharpe ratio (the tangency portfolio)
+// max (SIGMA w[i] * mean[i] - rf) / sqrt(w' * cov * w)
+// solved as the convex problem in y = k * w, k >= 0
+// min y' * cov * y subject to SIGMA y[i] * (mean[i] - rf) = 1,
+// SIGMA y[i] = k and the constraints scaled by k
+// rf = risk free return of the same period as Mean
+// returns MVSHARPE if no asset returns more than rf
+func (mv *MeanVariance) MaxSharpe(rf float64) (*Allocation, error) {
+	if mv.Mean == nil {
+		panic(NOOR)
+	}
+	mv.constraints() // checks the sizes
+	n := len(mv.Cov)
+	ex := make([]float64, n+1)
+	var s float64
+	for i, m := range mv.Mean {
+		ex[i] = m - rf
+		s = math.Max(s, ex[i])
+	}
+	if s <= 0 {
+		return nil, errors.New(MVSHARPE)
+	}
+	for i := range ex {
+		ex[i] /= s
+	}
+	var a [][]float64
+	var l, u []float64
+	// row of SIGMA y[i] over assets - c * k between lo and hi
+	row := func(c, lo, hi float64, assets ...int) {
+		r := make([]float64, n+1)
+		for _, i := range assets {
+			if i < 0 || i >= n {
+				panic(NOOR)
+			}
+			r[i] = 1
+		}
+		r[n] = -c
+		a = append(a, r)
+		l = append(l, lo)
+		u = append(u, hi)
+	}
+	inf := math.Inf(1)
+	a = append(a, ex)
+	l = append(l, 1)
+	u = append(u, 1)
+	all := make([]int, n)
+	for i := range all {
+		all[i] = i
+	}
+	row(1, 0, 0, all...)
+	row(-1, 0, inf) // k >= 0
+	for i := 0; i < n; i++ {
+		lo, hi := mv.bounds(i)
+		if !math.IsInf(lo, -1) {
+			row(lo, 0, inf, i)
+		}
+		if !math.IsInf(hi, 1) {
+			row(hi, -inf, 0, i)
+		}
+	}
+	for _, g := range mv.Groups {
+		if !math.IsInf(g.Min, -1) {
+			row(g.Min, 0, inf, g.Assets...)
+		}
+		if !math.IsInf(g.Max, 1) {
+			row(g.Max, -inf, 0, g.Assets...)
+		}
+	}
+	c := mv.scaledCov()
+	p := make([][]float64, n+1)
+	for i := range p {
+		p[i] = make([]float64, n+1)
+		if i < n {
+			copy(p[i], c[i])
+		}
+	}
+	y, ok := quadprog(p, make([]float64, n+1), a, l, u)
+	if !ok || y[n] <= 0 {
+		return nil, errors.New(MVFAIL)
+	}
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = y[i] / y[n]
+	}
+	return newAllocation(w, mv.Mean, mv.Cov), nil
+}
+
+// Frontier n portfolios on the efficient frontier with returns evenly
+// spaced from the MinVariance portfolio to the greatest return possible
+func (mv *MeanVariance) Frontier(n int) ([]Allocation, error) {
+	if n < 2 {
+		panic(NOOR)
+	}
+	lo, err := mv.MinVariance()
+	if err != nil {
+		return nil, err
+	}
+	hi, err := mv.maxReturn()
+	if err != nil {
+		return nil, err
+	}
+	f := make([]Allocation, n)
+	f[0], f[n-1] = *lo, *hi
+	for i := 1; i < n-1; i++ {
+		r := lo.Return + (hi.Return-lo.Return)*float64(i)/float64(n-1)
+		al, err := mv.TargetReturn(r)
+		if err != nil {
+			return nil, err
+		}
+		f[i] = *al
+	}
+	return f, nil
+}
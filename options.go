@@ -0,0 +1,251 @@
+package money
+
+/*
+The following types and functions are available
+
+OptionType Call or Put
+  (o OptionType) String() string
+BSM Black-Scholes-Merton (European options with continuous dividend yield)
+  BSM(s, k, t, r, q, v float64, o OptionType) float64
+BSMGreeks analytic Black-Scholes-Merton sensitivities
+  BSMGreeks(s, k, t, r, q, v float64, o OptionType) Greeks
+Black76 options on futures and forwards
+  Black76(f, k, t, r, v float64, o OptionType) float64
+Black76Greeks sensitivities of Black76 (Delta and Gamma to the forward)
+  Black76Greeks(f, k, t, r, v float64, o OptionType) Greeks
+Caplet Black-76 caplet (Call) or floorlet (Put) per unit notional
+  Caplet(f, k, t, tau, df, v float64, o OptionType) float64
+Cap cap (Call) or floor (Put) per unit notional on a YieldCurve
+  Cap(yc *YieldCurve, k, v float64, ts []float64, o OptionType) float64
+GK Garman-Kohlhagen (FX options)
+  GK(s, k, t, rd, rf, v float64, o OptionType) float64
+GKGreeks sensitivities of GK (Rho to rd, Phi to rf)
+  GKGreeks(s, k, t, rd, rf, v float64, o OptionType) Greeks
+*/
+
+import "math"
+
+// OptionType is the right conveyed by an option
+type OptionType int
+
+const (
+	Call OptionType = iota
+	Put
+)
+
+// String returns "call" or "put"
+func (o OptionType) String() string {
+	switch o {
+	case Call:
+		return "call"
+	case Put:
+		return "put"
+	}
+	return "unknown"
+}
+
+// optionType converts the legacy "c"/"p" strings into an OptionType
+func optionType(putcall string) (OptionType, bool) {
+	switch putcall {
+	case call:
+		return Call, true
+	case put:
+		return Put, true
+	}
+	return Call, false
+}
+
+// BSM Black-Scholes-Merton (European put and call options)
+// c = s * e^(-qt) * N(d1) - k * e^(-rt) * N(d2)
+// p = k * e^(-rt) * N(-d2) - s * e^(-qt) * N(-d1)
+// d1 = (ln(s/k) + (r - q + v^2/2)t) / (v * t^1/2)
+// d2 = d1 - v * t^1/2
+// s = Spot price
+// k = strike price
+// t = time to expire in years
+// r = risk free rate (continuous)
+// q = dividend yield (continuous)
+// v = volatility (sigma)
+// N = cumulative normal distribution (CND)
+// at t = 0 the intrinsic value is returned, at v = 0 the discounted
+// intrinsic value of the forward
+func BSM(s, k, t, r, q, v float64, o OptionType) float64 {
+	if s <= 0 || k <= 0 || t < 0 || v < 0 {
+		panic(NOOR)
+	}
+	if o != Call && o != Put {
+		panic(NOOR)
+	}
+	if t == 0 {
+		return intrinsic(s, k, o)
+	}
+	sq := s * math.Exp(-q*t)
+	kr := k * math.Exp(-r*t)
+	vt := v * math.Sqrt(t)
+	if vt == 0 {
+		return intrinsic(sq, kr, o)
+	}
+	d1 := (math.Log(s/k) + (r-q+v*v/2)*t) / vt
+	d2 := d1 - vt
+	if o == Call {
+		return sq*CND(d1) - kr*CND(d2)
+	}
+	return kr*CND(-d2) - sq*CND(-d1)
+}
+
+// intrinsic value of an option struck at k on s
+func intrinsic(s, k float64, o OptionType) float64 {
+	if o == Call {
+		return math.Max(s-k, 0)
+	}
+	return math.Max(k-s, 0)
+}
+
+// Greeks are the sensitivities of an option premium
+// Delta = dV/ds
+// Gamma = d2V/ds2
+// Vega  = dV/dv (per 1.00 of volatility, divide by 100 for one vol point)
+// Theta = dV/dt as calendar time passes (per year, divide by 365 per day)
+// Rho   = dV/dr (per 1.00 of rate)
+// Phi   = dV/dq (per 1.00 of dividend yield or foreign rate)
+// Vanna = d2V/ds dv
+// Volga = d2V/dv2 (also vomma)
+// Charm = dDelta/dt as calendar time passes (per year)
+type Greeks struct {
+	Delta, Gamma, Vega, Theta, Rho float64
+	Phi, Vanna, Volga, Charm       float64
+}
+
+// BSMGreeks analytic Black-Scholes-Merton Greeks
+// n = standard normal density (ND), N = cumulative normal (CND)
+// delta call = e^(-qt) * N(d1), put = -e^(-qt) * N(-d1)
+// gamma = e^(-qt) * n(d1) / (s * v * t^1/2)
+// vega = s * e^(-qt) * n(d1) * t^1/2
+// theta call = -s * e^(-qt) * n(d1) * v / (2 * t^1/2) - r * k * e^(-rt) * N(d2) + q * s * e^(-qt) * N(d1)
+// theta put = -s * e^(-qt) * n(d1) * v / (2 * t^1/2) + r * k * e^(-rt) * N(-d2) - q * s * e^(-qt) * N(-d1)
+// rho call = k * t * e^(-rt) * N(d2), put = -k * t * e^(-rt) * N(-d2)
+// phi call = -s * t * e^(-qt) * N(d1), put = s * t * e^(-qt) * N(-d1)
+// vanna = -e^(-qt) * n(d1) * d2 / v
+// volga = vega * d1 * d2 / v
+// charm call = q * e^(-qt) * N(d1) - e^(-qt) * n(d1) * (2(r-q)t - d2 * v * t^1/2) / (2t * v * t^1/2)
+// charm put = -q * e^(-qt) * N(-d1) - e^(-qt) * n(d1) * (2(r-q)t - d2 * v * t^1/2) / (2t * v * t^1/2)
+// parameters as for BSM
+// at t = 0 or v = 0 the option is worth its discounted forward intrinsic
+// value, so only Delta, Theta, Rho and Phi can be non zero
+func BSMGreeks(s, k, t, r, q, v float64, o OptionType) Greeks {
+	if s <= 0 || k <= 0 || t < 0 || v < 0 {
+		panic(NOOR)
+	}
+	if o != Call && o != Put {
+		panic(NOOR)
+	}
+	var g Greeks
+	dq := math.Exp(-q * t)
+	dr := math.Exp(-r * t)
+	sq, kr := s*dq, k*dr
+	vt := v * math.Sqrt(t)
+	if vt == 0 {
+		switch {
+		case o == Call && sq > kr:
+			g.Delta, g.Theta, g.Rho, g.Phi = dq, q*sq-r*kr, t*kr, -t*sq
+		case o == Put && kr > sq:
+			g.Delta, g.Theta, g.Rho, g.Phi = -dq, r*kr-q*sq, -t*kr, t*sq
+		}
+		return g
+	}
+	d1 := (math.Log(s/k) + (r-q+v*v/2)*t) / vt
+	d2 := d1 - vt
+	nd1 := ND(d1)
+	g.Gamma = dq * nd1 / (s * vt)
+	g.Vega = sq * nd1 * math.Sqrt(t)
+	g.Vanna = -dq * nd1 * d2 / v
+	g.Volga = g.Vega * d1 * d2 / v
+	decay := -sq * nd1 * v / (2 * math.Sqrt(t))
+	drift := dq * nd1 * (2*(r-q)*t - d2*vt) / (2 * t * vt)
+	if o == Call {
+		g.Delta = dq * CND(d1)
+		g.Theta = decay - r*kr*CND(d2) + q*sq*CND(d1)
+		g.Rho = t * kr * CND(d2)
+		g.Phi = -t * sq * CND(d1)
+		g.Charm = q*dq*CND(d1) - drift
+		return g
+	}
+	g.Delta = -dq * CND(-d1)
+	g.Theta = decay + r*kr*CND(-d2) - q*sq*CND(-d1)
+	g.Rho = -t * kr * CND(-d2)
+	g.Phi = t * sq * CND(-d1)
+	g.Charm = -q*dq*CND(-d1) - drift
+	return g
+}
+
+// Black76 Black-76 (European options on futures and forwards)
+// c = e^(-rt) * (f * N(d1) - k * N(d2))
+// p = e^(-rt) * (k * N(-d2) - f * N(-d1))
+// d1 = (ln(f/k) + (v^2/2)t) / (v * t^1/2)
+// d2 = d1 - v * t^1/2
+// f = futures or forward price
+// k, t, r, v = as for BSM
+// equal to BSM with s = f and q = r
+func Black76(f, k, t, r, v float64, o OptionType) float64 {
+	return BSM(f, k, t, r, r, v, o)
+}
+
+// Black76Greeks Black-76 Greeks
+// Delta, Gamma, Vanna and Charm are taken to the forward f, Rho moves r
+// with f held fixed (rho = -t * premium) and Phi is zero
+func Black76Greeks(f, k, t, r, v float64, o OptionType) Greeks {
+	g := BSMGreeks(f, k, t, r, r, v, o)
+	g.Rho = -t * Black76(f, k, t, r, v, o)
+	g.Phi = 0
+	return g
+}
+
+// Caplet Black-76 caplet (Call) or floorlet (Put) per unit notional
+// caplet = tau * df * (f * N(d1) - k * N(d2))
+// f = forward rate for the period
+// k = cap or floor rate
+// t = time to the rate fixing in years
+// tau = accrual fraction of the period
+// df = discount factor to the payment date
+// v = volatility of the forward rate
+func Caplet(f, k, t, tau, df, v float64, o OptionType) float64 {
+	return tau * df * Black76(f, k, t, 0, v, o)
+}
+
+// Cap cap (Call) or floor (Put) per unit notional, the sum of its caplets
+// forward rates are read off the curve: f = (df(t1) / df(t2) - 1) / tau
+// yc = discount and forward curve
+// k = cap or floor rate
+// v = flat volatility
+// ts = period boundaries in years, the caplet on ts[i] to ts[i+1] fixes
+// at ts[i] and pays at ts[i+1] with tau = ts[i+1] - ts[i]
+func Cap(yc *YieldCurve, k, v float64, ts []float64, o OptionType) float64 {
+	var sum float64
+	for i := 0; i+1 < len(ts); i++ {
+		tau := ts[i+1] - ts[i]
+		if tau <= 0 {
+			panic(NOOR)
+		}
+		df := yc.DF(ts[i+1])
+		f := (yc.DF(ts[i])/df - 1) / tau
+		sum += Caplet(f, k, ts[i], tau, df, v, o)
+	}
+	return sum
+}
+
+// GK Garman-Kohlhagen (European FX options)
+// c = s * e^(-rf*t) * N(d1) - k * e^(-rd*t) * N(d2)
+// d1 = (ln(s/k) + (rd - rf + v^2/2)t) / (v * t^1/2)
+// s = spot exchange rate (domestic per unit of foreign)
+// rd = domestic risk free rate
+// rf = foreign risk free rate
+// k, t, v = as for BSM
+// equal to BSM with r = rd and q = rf, the premium is in domestic currency
+func GK(s, k, t, rd, rf, v float64, o OptionType) float64 {
+	return BSM(s, k, t, rd, rf, v, o)
+}
+
+// GKGreeks Garman-Kohlhagen Greeks, Rho to rd and Phi to rf
+func GKGreeks(s, k, t, rd, rf, v float64, o OptionType) Greeks {
+	return BSMGreeks(s, k, t, rd, rf, v, o)
+}
@@ -0,0 +1,140 @@
+package money
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBSM(t *testing.T) {
+	tests := []struct {
+		name             string
+		s, k, t, r, q, v float64
+		o                OptionType
+		want, tol        float64
+	}{
+		{"Hull call", 42, 40, 0.5, 0.1, 0, 0.2, Call, 4.76, 0.005},
+		{"Hull put", 42, 40, 0.5, 0.1, 0, 0.2, Put, 0.81, 0.005},
+		{"Haug put with yield", 100, 95, 0.5, 0.1, 0.05, 0.2, Put, 2.4648, 0.00005},
+		{"out of the money put", 100, 110, 1, 0.05, 0, 0, Put, 4.635236695078547, 1e-12},
+		{"expired call", 42, 40, 0, 0.1, 0, 0.2, Call, 2, 0},
+		{"zero volatility call", 100, 90, 1, 0.05, 0.02, 0, Call, 100*math.Exp(-0.02) - 90*math.Exp(-0.05), 1e-12},
+	}
+	for _, tt := range tests {
+		if got := BSM(tt.s, tt.k, tt.t, tt.r, tt.q, tt.v, tt.o); math.Abs(got-tt.want) > tt.tol {
+			t.Errorf("%s: BSM = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBSMParity(t *testing.T) {
+	// c - p = s * e^(-qt) - k * e^(-rt)
+	tests := []struct{ s, k, t, r, q, v float64 }{
+		{42, 40, 0.5, 0.1, 0, 0.2},
+		{100, 95, 0.5, 0.1, 0.05, 0.2},
+		{100, 120, 2, 0.03, 0.01, 0.4},
+		{50, 50, 0.1, 0, 0, 0.6},
+	}
+	for _, tt := range tests {
+		c := BSM(tt.s, tt.k, tt.t, tt.r, tt.q, tt.v, Call)
+		p := BSM(tt.s, tt.k, tt.t, tt.r, tt.q, tt.v, Put)
+		want := tt.s*math.Exp(-tt.q*tt.t) - tt.k*math.Exp(-tt.r*tt.t)
+		if math.Abs(c-p-want) > 1e-10 {
+			t.Errorf("%v: c - p = %v, want %v", tt, c-p, want)
+		}
+	}
+}
+
+func TestBS(t *testing.T) {
+	tests := []struct {
+		putcall   string
+		want, tol float64
+	}{
+		{call, 4.76, 0.005},
+		{put, 0.81, 0.005},
+	}
+	for _, tt := range tests {
+		if got := BS(42, 40, 0.5, 0.1, 0.2, tt.putcall); math.Abs(got-tt.want) > tt.tol {
+			t.Errorf("BS %q = %v, want %v", tt.putcall, got, tt.want)
+		}
+	}
+	for _, tt := range []struct {
+		name       string
+		s, k, t, v float64
+		putcall    string
+	}{
+		{"unknown putcall", 42, 40, 0.5, 0.2, "x"},
+		{"zero spot", 0, 40, 0.5, 0.2, call},
+		{"zero strike", 42, 0, 0.5, 0.2, put},
+		{"negative time", 42, 40, -0.5, 0.2, call},
+		{"negative volatility", 42, 40, 0.5, -0.2, put},
+	} {
+		if got := BS(tt.s, tt.k, tt.t, 0.1, tt.v, tt.putcall); !math.IsNaN(got) {
+			t.Errorf("BS %s = %v, want NaN", tt.name, got)
+		}
+	}
+}
+
+// bumped Greeks by central differences of price in s, t, r, q and v
+func bumped(price func(s, t, r, q, v float64) float64, s, t, r, q, v float64) Greeks {
+	const h = 1e-4
+	delta := func(s, t, v float64) float64 {
+		return (price(s*(1+h), t, r, q, v) - price(s*(1-h), t, r, q, v)) / (2 * s * h)
+	}
+	vega := func(v float64) float64 {
+		return (price(s, t, r, q, v+h) - price(s, t, r, q, v-h)) / (2 * h)
+	}
+	var g Greeks
+	g.Delta = delta(s, t, v)
+	g.Gamma = (price(s*(1+h), t, r, q, v) - 2*price(s, t, r, q, v) + price(s*(1-h), t, r, q, v)) / (s * h * s * h)
+	g.Vega = vega(v)
+	g.Theta = -(price(s, t+h, r, q, v) - price(s, t-h, r, q, v)) / (2 * h)
+	g.Rho = (price(s, t, r+h, q, v) - price(s, t, r-h, q, v)) / (2 * h)
+	g.Phi = (price(s, t, r, q+h, v) - price(s, t, r, q-h, v)) / (2 * h)
+	g.Vanna = (delta(s, t, v+h) - delta(s, t, v-h)) / (2 * h)
+	g.Volga = (vega(v+h) - vega(v-h)) / (2 * h)
+	g.Charm = -(delta(s, t+h, v) - delta(s, t-h, v)) / (2 * h)
+	return g
+}
+
+func TestGreeks(t *testing.T) {
+	tests := []struct {
+		name             string
+		s, k, t, r, q, v float64
+		price            func(s, k, t, r, q, v float64, o OptionType) float64
+		greeks           func(s, k, t, r, q, v float64, o OptionType) Greeks
+	}{
+		{"BSM", 42, 40, 0.5, 0.1, 0.03, 0.2, BSM, BSMGreeks},
+		{"BSM long dated", 100, 130, 3, 0.02, 0.04, 0.35, BSM, BSMGreeks},
+		{"GK", 1.10, 1.12, 0.75, 0.04, 0.025, 0.1, GK, GKGreeks},
+		{"Black76",
+			95, 100, 1, 0.05, 0, 0.3,
+			func(f, k, t, r, _, v float64, o OptionType) float64 { return Black76(f, k, t, r, v, o) },
+			func(f, k, t, r, _, v float64, o OptionType) Greeks { return Black76Greeks(f, k, t, r, v, o) }},
+	}
+	for _, tt := range tests {
+		for _, o := range []OptionType{Call, Put} {
+			got := tt.greeks(tt.s, tt.k, tt.t, tt.r, tt.q, tt.v, o)
+			want := bumped(func(s, t, r, q, v float64) float64 {
+				return tt.price(s, tt.k, t, r, q, v, o)
+			}, tt.s, tt.t, tt.r, tt.q, tt.v)
+			for _, c := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"Delta", got.Delta, want.Delta},
+				{"Gamma", got.Gamma, want.Gamma},
+				{"Vega", got.Vega, want.Vega},
+				{"Theta", got.Theta, want.Theta},
+				{"Rho", got.Rho, want.Rho},
+				{"Phi", got.Phi, want.Phi},
+				{"Vanna", got.Vanna, want.Vanna},
+				{"Volga", got.Volga, want.Volga},
+				{"Charm", got.Charm, want.Charm},
+			} {
+				if math.Abs(c.got-c.want) > 1e-4*math.Max(1, math.Abs(c.want)) {
+					t.Errorf("%s %v %s = %v, bumped %v", tt.name, o, c.name, c.got, c.want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package money
+
+/*
+The following types and functions are available
+
+r holds periodic simple returns oldest first, rf the risk free return of
+the same periods (nil for none), n the periods per year (see Periods)
+
+Performance every measure below of a fund against a benchmark
+  NewPerformance(r, benchmark, rf []float64, n float64) *Performance
+Periods periods per year of a data Frequency
+  Periods(f Frequency) float64
+Sharpe excess return per unit of volatility
+  Sharpe(r, rf []float64, n float64) float64
+Sortino excess return per unit of downside deviation
+  Sortino(r, rf []float64, n float64) float64
+Treynor excess return per unit of beta
+  Treynor(r, benchmark, rf []float64, n float64) float64
+Information active return per unit of tracking error
+  Information(r, benchmark []float64, n float64) float64
+Calmar annual return over the maximum drawdown
+  Calmar(r []float64, n float64) float64
+Omega probability weighted gains over losses about a threshold
+  Omega(r []float64, threshold float64) float64
+MaxDrawdown largest peak to trough fall with its dates and duration
+  MaxDrawdown(r []float64) Drawdown
+*/
+
+import "math"
+
+// Periods periods per year of returns sampled at frequency f
+// Daily = TradingDays, Weekly = 52, Monthly = 12
+func Periods(f Frequency) float64 {
+	switch f {
+	case Weekly:
+		return 52
+	case Monthly:
+		return 12
+	}
+	return TradingDays
+}
+
+// excess returns over rf, r itself when rf is nil
+func excess(r, rf []float64) []float64 {
+	if rf == nil {
+		return r
+	}
+	return ExcessReturns(r, rf)
+}
+
+// Sharpe ratio annualised
+// sharpe = Mean(x) / SDs(x) * sqrt(n), x = r - rf
+func Sharpe(r, rf []float64, n float64) float64 {
+	x := excess(r, rf)
+	return Mean(x) / SDs(x) * math.Sqrt(n)
+}
+
+// Sortino ratio annualised
+// sortino = Mean(x) / dd * sqrt(n), x = r - rf
+// dd = sqrt(SIGMA min(x[i], 0) ^ 2 / len(x)), the downside deviation
+func Sortino(r, rf []float64, n float64) float64 {
+	x := excess(r, rf)
+	var dd float64
+	for _, v := range x {
+		if v < 0 {
+			dd += v * v
+		}
+	}
+	dd = math.Sqrt(dd / float64(len(x)))
+	return Mean(x) / dd * math.Sqrt(n)
+}
+
+// Treynor ratio annualised
+// treynor = Mean(r - rf) * n / beta
+// beta = slope of the fund on the benchmark excess returns (see R)
+func Treynor(r, benchmark, rf []float64, n float64) float64 {
+	x := excess(r, rf)
+	_, beta, _ := R(excess(benchmark, rf), x)
+	return Mean(x) * n / beta
+}
+
+// Information ratio annualised
+// ir = Mean(a) / SDs(a) * sqrt(n), a = r - benchmark the active return
+// SDs(a) * sqrt(n) is the annual tracking error
+func Information(r, benchmark []float64, n float64) float64 {
+	a := ExcessReturns(r, benchmark)
+	return Mean(a) / SDs(a) * math.Sqrt(n)
+}
+
+// Calmar ratio
+// calmar = AnnualReturn(r, n) / MaxDrawdown(r).Depth
+func Calmar(r []float64, n float64) float64 {
+	return AnnualReturn(r, n) / MaxDrawdown(r).Depth
+}
+
+// Omega ratio about a threshold return
+// omega = SIGMA max(r[i] - L, 0) / SIGMA max(L - r[i], 0)
+// L = threshold per period ex. 0 or the risk free return
+func Omega(r []float64, threshold float64) float64 {
+	if len(r) == 0 {
+		panic(NOOR)
+	}
+	var up, down float64
+	for _, v := range r {
+		if v > threshold {
+			up += v - threshold
+		} else {
+			down += threshold - v
+		}
+	}
+	return up / down
+}
+
+// Drawdown largest fall in cumulative value from a previous peak
+// Depth = fall as a fraction of the peak value ex. 0.25 for 25%
+// Peak, Trough, Recovery = return index after which the value peaked,
+// bottomed and first regained the peak (-1 before the first return and
+// Recovery -1 if not regained)
+// Duration = periods from the peak to the recovery, or to the last return
+// when not recovered
+type Drawdown struct {
+	Depth                  float64
+	Peak, Trough, Recovery int
+	Duration               int
+}
+
+// MaxDrawdown largest peak to trough fall of the compounded returns
+// value[i] = (1 + r[0]) * ... * (1 + r[i]), starting from 1
+// dd[i] = 1 - value[i] / max(value[0..i])
+func MaxDrawdown(r []float64) Drawdown {
+	if len(r) == 0 {
+		panic(NOOR)
+	}
+	d := Drawdown{Peak: -1, Trough: -1, Recovery: -1}
+	value, peakValue := 1.0, 1.0
+	peak := -1
+	for i, v := range r {
+		value *= 1 + v
+		if value >= peakValue {
+			peakValue, peak = value, i
+			continue
+		}
+		if dd := 1 - value/peakValue; dd > d.Depth {
+			d.Depth, d.Peak, d.Trough = dd, peak, i
+		}
+	}
+	if d.Depth == 0 {
+		d.Peak = len(r) - 1
+		d.Trough = d.Peak
+		d.Recovery = d.Peak
+		return d
+	}
+	value = 1
+	peakValue = 1
+	for i, v := range r {
+		value *= 1 + v
+		if i == d.Peak {
+			peakValue = value
+		}
+		if i > d.Trough && value >= peakValue {
+			d.Recovery = i
+			break
+		}
+	}
+	end := d.Recovery
+	if end < 0 {
+		end = len(r) - 1
+	}
+	d.Duration = end - d.Peak
+	return d
+}
+
+// Performance risk-adjusted performance of a fund, annualised
+// Return = AnnualReturn (geometric), Volatility = SDs * sqrt(n)
+// Alpha = intercept * n and Beta = slope of the fund on the benchmark
+// excess returns (see R), TrackingError = SDs(r - benchmark) * sqrt(n)
+// measures needing a benchmark are NaN without one
+type Performance struct {
+	Return, Volatility   float64
+	Sharpe, Sortino      float64
+	Treynor, Information float64
+	Calmar, Omega        float64
+	Alpha, Beta          float64
+	TrackingError        float64
+	Drawdown             Drawdown
+}
+
+// NewPerformance every performance measure of the returns r
+// benchmark = benchmark returns for the same periods (nil for none)
+// rf = risk free returns for the same periods (nil for none), Omega is
+// taken about the mean risk free return
+// n = periods per year ex. Periods(Monthly)
+func NewPerformance(r, benchmark, rf []float64, n float64) *Performance {
+	x := excess(r, rf)
+	p := &Performance{
+		Return:     AnnualReturn(r, n),
+		Volatility: SDs(r) * math.Sqrt(n),
+		Sharpe:     Sharpe(r, rf, n),
+		Sortino:    Sortino(r, rf, n),
+		Calmar:     Calmar(r, n),
+		Drawdown:   MaxDrawdown(r),
+	}
+	threshold := 0.0
+	if rf != nil {
+		threshold = Mean(rf)
+	}
+	p.Omega = Omega(r, threshold)
+	nan := math.NaN()
+	p.Treynor, p.Information, p.Alpha, p.Beta, p.TrackingError = nan, nan, nan, nan, nan
+	if benchmark != nil {
+		a, b, _ := R(excess(benchmark, rf), x)
+		p.Alpha, p.Beta = a*n, b
+		p.Treynor = Mean(x) * n / b
+		p.Information = Information(r, benchmark, n)
+		p.TrackingError = SDs(ExcessReturns(r, benchmark)) * math.Sqrt(n)
+	}
+	return p
+}
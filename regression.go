@@ -0,0 +1,165 @@
+package money
+
+/*
+The following types and functions are available
+
+OLS result of an ordinary least squares regression
+Regress regression of y on one or more regressors
+  Regress(y []float64, xs [][]float64, intercept bool) (*OLS, error)
+Predict fitted value for a set of regressor values
+  (o *OLS) Predict(x []float64) float64
+*/
+
+import (
+	"errors"
+	"math"
+)
+
+// OLS ordinary least squares regression
+// y[t] = c[0] + c[1] * x[0][t] + ... + c[k] * x[k-1][t] + e[t]
+// with the intercept c[0] only when requested
+type OLS struct {
+	Coef      []float64 // coefficients, the intercept first if any
+	StdErr    []float64 // standard errors of Coef
+	T         []float64 // t-statistics Coef / StdErr
+	P         []float64 // two sided p-values of T
+	R2        float64   // coefficient of determination
+	AdjR2     float64   // R2 adjusted for the degrees of freedom
+	SE        float64   // standard error of the regression
+	Residuals []float64 // e[t] = y[t] - fitted value
+	DW        float64   // Durbin-Watson statistic of the residuals
+	N, DF     int       // observations and residual degrees of freedom
+	intercept bool
+}
+
+// Regress ordinary least squares regression of y on the regressors xs
+// c = (X'X)^-1 X'y, var(c) = s^2 (X'X)^-1, s^2 = SIGMA e[t]^2 / (n - k)
+// y = dependent values ex. fund excess returns
+// xs = one slice per regressor ex. Fama-French factors, each len(y)
+// intercept = include a constant term (alpha)
+// R2 is centred with an intercept and uncentred without one
+// returns OLSRANK if the regressors are collinear or n <= k
+func Regress(y []float64, xs [][]float64, intercept bool) (*OLS, error) {
+	n := len(y)
+	for _, x := range xs {
+		if len(x) != n {
+			panic(NOOR)
+		}
+	}
+	k := len(xs)
+	if intercept {
+		k++
+	}
+	if k == 0 {
+		panic(NOOR)
+	}
+	if n <= k {
+		return nil, errors.New(OLSRANK)
+	}
+	// row t of the design matrix
+	row := func(t int, r []float64) {
+		j := 0
+		if intercept {
+			r[0] = 1
+			j = 1
+		}
+		for _, x := range xs {
+			r[j] = x[t]
+			j++
+		}
+	}
+	xtx := square(k)
+	xty := make([]float64, k)
+	r := make([]float64, k)
+	for t := 0; t < n; t++ {
+		row(t, r)
+		for i := 0; i < k; i++ {
+			xty[i] += r[i] * y[t]
+			for j := 0; j <= i; j++ {
+				xtx[i][j] += r[i] * r[j]
+			}
+		}
+	}
+	for i := 0; i < k; i++ {
+		for j := 0; j < i; j++ {
+			xtx[j][i] = xtx[i][j]
+		}
+	}
+	inv, ok := invert(xtx)
+	if !ok {
+		return nil, errors.New(OLSRANK)
+	}
+	o := &OLS{
+		Coef:      make([]float64, k),
+		StdErr:    make([]float64, k),
+		T:         make([]float64, k),
+		P:         make([]float64, k),
+		Residuals: make([]float64, n),
+		N:         n,
+		DF:        n - k,
+		intercept: intercept,
+	}
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			o.Coef[i] += inv[i][j] * xty[j]
+		}
+	}
+	my := 0.0
+	if intercept {
+		my = Mean(y)
+	}
+	var sse, sst, dw float64
+	for t := 0; t < n; t++ {
+		row(t, r)
+		f := 0.0
+		for i := 0; i < k; i++ {
+			f += o.Coef[i] * r[i]
+		}
+		e := y[t] - f
+		o.Residuals[t] = e
+		sse += e * e
+		sst += (y[t] - my) * (y[t] - my)
+		if t > 0 {
+			d := e - o.Residuals[t-1]
+			dw += d * d
+		}
+	}
+	s2 := sse / float64(o.DF)
+	o.SE = math.Sqrt(s2)
+	df := float64(o.DF)
+	for i := 0; i < k; i++ {
+		o.StdErr[i] = math.Sqrt(s2 * inv[i][i])
+		o.T[i] = o.Coef[i] / o.StdErr[i]
+		// p = 2 * (1 - TCD(|t|, df)), without the cancellation
+		o.P[i] = betaInc(df/2, 0.5, df/(df+o.T[i]*o.T[i]))
+	}
+	if sst > 0 {
+		o.R2 = 1 - sse/sst
+		dfT := float64(n)
+		if intercept {
+			dfT--
+		}
+		o.AdjR2 = 1 - (1-o.R2)*dfT/df
+	}
+	if sse > 0 {
+		o.DW = dw / sse
+	}
+	return o, nil
+}
+
+// Predict fitted value for one value of each regressor, in the order of xs
+func (o *OLS) Predict(x []float64) float64 {
+	c := o.Coef
+	f := 0.0
+	if o.intercept {
+		f = c[0]
+		c = c[1:]
+	}
+	if len(x) != len(c) {
+		panic(NOOR)
+	}
+	for i := range x {
+		f += c[i] * x[i]
+	}
+	return f
+}
@@ -0,0 +1,150 @@
+package money
+
+/*
+The following functions are available
+
+SimpleReturns periodic simple returns of a price series
+  SimpleReturns(p []float64) []float64
+LogReturns periodic continuously compounded returns of a price series
+  LogReturns(p []float64) []float64
+TotalReturns simple returns including dividends paid in each period
+  TotalReturns(p, d []float64) []float64
+TotalReturn return from one Quote to a later Quote including its Dividend
+  (q *Quote) TotalReturn(prev *Quote) float64
+ExcessReturns returns less the risk free return of the same period
+  ExcessReturns(r, rf []float64) []float64
+CumulativeReturns compounded return to date at each period
+  CumulativeReturns(r []float64) []float64
+GeoMean geometric mean periodic return
+  GeoMean(r []float64) float64
+Annualise annual return equivalent to a periodic return
+  Annualise(r, n float64) float64
+AnnualReturn annualised geometric return of a return series
+  AnnualReturn(r []float64, n float64) float64
+*/
+
+import "math"
+
+// SimpleReturns periodic simple returns
+// r[i-1] = p[i] / p[i-1] - 1
+// p = prices, oldest first
+func SimpleReturns(p []float64) []float64 {
+	if len(p) < 2 {
+		panic(NOOR)
+	}
+	r := make([]float64, len(p)-1)
+	for i := 1; i < len(p); i++ {
+		r[i-1] = p[i]/p[i-1] - 1
+	}
+	return r
+}
+
+// LogReturns periodic continuously compounded returns
+// r[i-1] = ln(p[i] / p[i-1])
+// p = prices, oldest first
+func LogReturns(p []float64) []float64 {
+	if len(p) < 2 {
+		panic(NOOR)
+	}
+	r := make([]float64, len(p)-1)
+	for i := 1; i < len(p); i++ {
+		r[i-1] = math.Log(p[i] / p[i-1])
+	}
+	return r
+}
+
+// TotalReturns periodic simple returns including dividends
+// r[i-1] = (p[i] + d[i] - p[i-1]) / p[i-1]
+// p = prices, oldest first
+// d = dividend per share paid in the period ending at p[i] (d[0] unused)
+// p and d must correspond, be the same len()
+func TotalReturns(p, d []float64) []float64 {
+	if len(p) < 2 || len(p) != len(d) {
+		panic(NOOR)
+	}
+	r := make([]float64, len(p)-1)
+	for i := 1; i < len(p); i++ {
+		r[i-1] = (p[i] + d[i] - p[i-1]) / p[i-1]
+	}
+	return r
+}
+
+// TotalReturn simple return from prev to q taking q.Dividend as paid in
+// between
+// r = (q.Price + q.Dividend - prev.Price) / prev.Price
+func (q *Quote) TotalReturn(prev *Quote) float64 {
+	if prev.Price.Value() == 0 {
+		panic(DBZ)
+	}
+	return (q.Price.Get() + q.Dividend.Get() - prev.Price.Get()) / prev.Price.Get()
+}
+
+// ExcessReturns returns over the risk free rate
+// x[i] = r[i] - rf[i]
+// rf = risk free return for the same periods as r
+// r and rf must correspond, be the same len()
+func ExcessReturns(r, rf []float64) []float64 {
+	if len(r) != len(rf) {
+		panic(NOOR)
+	}
+	x := make([]float64, len(r))
+	for i := range r {
+		x[i] = r[i] - rf[i]
+	}
+	return x
+}
+
+// CumulativeReturns compounded return to date
+// c[i] = (1 + r[0]) * (1 + r[1]) * ... * (1 + r[i]) - 1
+// r = periodic simple returns
+func CumulativeReturns(r []float64) []float64 {
+	c := make([]float64, len(r))
+	g := 1.0
+	for i, x := range r {
+		g *= 1 + x
+		c[i] = g - 1
+	}
+	return c
+}
+
+// GeoMean geometric mean periodic return
+// g = ((1 + r[0]) * ... * (1 + r[n-1]))^(1/n) - 1
+// r = periodic simple returns
+// summed as logs, -1 if any return is -100% (wiped out) and NaN if any
+// is less
+func GeoMean(r []float64) float64 {
+	if len(r) == 0 {
+		panic(NOOR)
+	}
+	var sum float64
+	wiped := false
+	for _, x := range r {
+		switch {
+		case x < -1 || math.IsNaN(x):
+			return math.NaN()
+		case x == -1:
+			wiped = true
+		default:
+			sum += math.Log1p(x)
+		}
+	}
+	if wiped {
+		return -1
+	}
+	return math.Expm1(sum / float64(len(r)))
+}
+
+// Annualise annual return equivalent to a periodic return
+// a = (1 + r)^n - 1
+// r = periodic simple return
+// n = periods per year ex. TradingDays, 52 or 12
+func Annualise(r, n float64) float64 {
+	return math.Pow(1+r, n) - 1
+}
+
+// AnnualReturn annualised geometric return of a return series
+// a = (1 + GeoMean(r))^n - 1
+// n = periods per year
+func AnnualReturn(r []float64, n float64) float64 {
+	return Annualise(GeoMean(r), n)
+}
@@ -0,0 +1,45 @@
+package money
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGeoMean(t *testing.T) {
+	tests := []struct {
+		name string
+		r    []float64
+		want float64
+	}{
+		{"flat", []float64{0.1, -0.1}, math.Sqrt(0.99) - 1},
+		{"wiped out", []float64{0.2, -1, 0.5}, -1},
+		{"below -100%", []float64{0.2, -1.5, -1}, math.NaN()},
+	}
+	for _, tt := range tests {
+		got := GeoMean(tt.r)
+		if math.IsNaN(got) != math.IsNaN(tt.want) || !math.IsNaN(tt.want) && math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s: GeoMean = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if got := AnnualReturn([]float64{0.01, -1}, 12); got != -1 {
+		t.Errorf("AnnualReturn wiped out = %v, want -1", got)
+	}
+}
+
+func TestTimeSeriesTotalReturns(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	p := NewTimeSeries([]time.Time{day(1), day(4), day(8), day(11)}, []float64{100, 102, 99, 101})
+	// paid before the first price (left out), between prices and on a price date
+	d := NewTimeSeries([]time.Time{day(1), day(6), day(7), day(11)}, []float64{5, 1, 0.5, 2})
+	got := p.TotalReturns(d)
+	want := []float64{102.0/100 - 1, (99 + 1.5 - 102) / 102, (101 + 2 - 99) / 99.0}
+	if len(got.Times) != 3 || !got.Times[0].Equal(day(4)) {
+		t.Fatalf("TotalReturns times = %v", got.Times)
+	}
+	for i := range want {
+		if math.Abs(got.Values[i]-want[i]) > 1e-12 {
+			t.Errorf("TotalReturns[%d] = %v, want %v", i, got.Values[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package money
+
+/*
+The following functions are available
+
+RiskParity weights whose risk contributions match a budget (equal risk
+contribution when budget is nil)
+  RiskParity(cov [][]float64, budget []float64) (*Allocation, error)
+HRP hierarchical risk parity weights (Lopez de Prado)
+  HRP(cov [][]float64) *Allocation
+*/
+
+import (
+	"errors"
+	"math"
+)
+
+// RiskParity long only weights with risk contributions in proportion to
+// budget (see Allocation.RiskContrib)
+// w[i] * (cov * w)[i] / (w' * cov * w) = b[i]
+// found as the minimum of y' * cov * y / 2 - SIGMA b[i] * ln(y[i]) by
+// cyclical coordinate descent, each step solving its quadratic in y[i]
+// y[i] = (-c + sqrt(c^2 + 4 * cov[i][i] * b[i])) / (2 * cov[i][i])
+// c = SIGMA (j != i) cov[i][j] * y[j], then w = y / SIGMA y
+// cov = covariance matrix ex. CovMatrix
+// budget = risk share of each asset, nil for equal shares, scaled to sum 1
+// returns RPFAIL if the iteration does not converge
+func RiskParity(cov [][]float64, budget []float64) (*Allocation, error) {
+	n := len(cov)
+	if n == 0 || (budget != nil && len(budget) != n) {
+		panic(NOOR)
+	}
+	b := make([]float64, n)
+	var sb float64
+	for i := range b {
+		b[i] = 1
+		if budget != nil {
+			b[i] = budget[i]
+		}
+		if b[i] <= 0 || cov[i][i] <= 0 {
+			panic(NOOR)
+		}
+		sb += b[i]
+	}
+	y := make([]float64, n)
+	for i := range y {
+		b[i] /= sb
+		y[i] = 1 / math.Sqrt(cov[i][i])
+	}
+	const maxIter = 10000
+	for it := 0; it < maxIter; it++ {
+		var change float64
+		for i := 0; i < n; i++ {
+			var c float64
+			for j := 0; j < n; j++ {
+				if j != i {
+					c += cov[i][j] * y[j]
+				}
+			}
+			yi := (-c + math.Sqrt(c*c+4*cov[i][i]*b[i])) / (2 * cov[i][i])
+			change = math.Max(change, math.Abs(yi-y[i])/yi)
+			y[i] = yi
+		}
+		if change < 1e-13 {
+			var s float64
+			for _, v := range y {
+				s += v
+			}
+			w := make([]float64, n)
+			for i := range w {
+				w[i] = y[i] / s
+			}
+			return newAllocation(w, nil, cov), nil
+		}
+	}
+	return nil, errors.New(RPFAIL)
+}
+
+// HRP hierarchical risk parity weights (Lopez de Prado 2016)
+// 1 distance d[i][j] = sqrt((1 - corr[i][j]) / 2) and the distance
+// between assets D[i][j] = sqrt(SIGMA (k) (d[k][i] - d[k][j])^2)
+// 2 single linkage clustering on D, assets ordered as the leaves of the
+// tree so that similar assets sit together (quasi-diagonalisation)
+// 3 recursive bisection of the ordered assets, each half weighted by
+// 1 - var / (var + var of the other half), where var is the variance of
+// the half's inverse variance portfolio
+// cov = covariance matrix ex. CovMatrix
+func HRP(cov [][]float64) *Allocation {
+	n := len(cov)
+	if n == 0 {
+		panic(NOOR)
+	}
+	corr := CovToCorr(cov)
+	d := square(n)
+	for i := range d {
+		for j := range d {
+			d[i][j] = math.Sqrt(math.Max(0, (1-corr[i][j])/2))
+		}
+	}
+	dist := square(n)
+	for i := range dist {
+		for j := 0; j < i; j++ {
+			var s float64
+			for k := 0; k < n; k++ {
+				s += (d[k][i] - d[k][j]) * (d[k][i] - d[k][j])
+			}
+			dist[i][j], dist[j][i] = math.Sqrt(s), math.Sqrt(s)
+		}
+	}
+	order := linkageOrder(dist)
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 1
+	}
+	var bisect func(items []int)
+	bisect = func(items []int) {
+		if len(items) < 2 {
+			return
+		}
+		h := len(items) / 2
+		l, r := items[:h], items[h:]
+		vl, vr := clusterVar(cov, l), clusterVar(cov, r)
+		a := 1 - vl/(vl+vr)
+		for _, i := range l {
+			w[i] *= a
+		}
+		for _, i := range r {
+			w[i] *= 1 - a
+		}
+		bisect(l)
+		bisect(r)
+	}
+	bisect(order)
+	return newAllocation(w, nil, cov)
+}
+
+// linkageOrder leaf order of the single linkage clustering of the
+// distance matrix dist, each merged cluster listing the child with the
+// lower cluster id first, as scipy's linkage: assets are ids 0 to n-1 and
+// the cluster formed at merge m (from 0) is id n + m
+func linkageOrder(dist [][]float64) []int {
+	n := len(dist)
+	clusters := make([][]int, n)
+	id := make([]int, n) // cluster id held in each slot
+	for i := range clusters {
+		clusters[i] = []int{i}
+		id[i] = i
+	}
+	// link distance between clusters, the closest pair of members
+	link := square(n)
+	for i := range link {
+		copy(link[i], dist[i])
+	}
+	alive := make([]bool, n)
+	for i := range alive {
+		alive[i] = true
+	}
+	for merges := 1; merges < n; merges++ {
+		bi, bj := -1, -1
+		for i := 0; i < n; i++ {
+			for j := i + 1; alive[i] && j < n; j++ {
+				if alive[j] && (bi < 0 || link[i][j] < link[bi][bj]) {
+					bi, bj = i, j
+				}
+			}
+		}
+		if id[bi] < id[bj] {
+			clusters[bi] = append(clusters[bi], clusters[bj]...)
+		} else {
+			clusters[bi] = append(clusters[bj], clusters[bi]...)
+		}
+		id[bi] = n + merges - 1
+		alive[bj] = false
+		for k := 0; k < n; k++ {
+			m := math.Min(link[bi][k], link[bj][k])
+			link[bi][k], link[k][bi] = m, m
+		}
+	}
+	for i := range alive {
+		if alive[i] {
+			return clusters[i]
+		}
+	}
+	return nil
+}
+
+// clusterVar variance of the inverse variance portfolio of the assets
+// w[i] = (1 / cov[i][i]) / SIGMA (1 / cov[j][j])
+func clusterVar(cov [][]float64, items []int) float64 {
+	w := make([]float64, len(items))
+	var s float64
+	for k, i := range items {
+		w[k] = 1 / cov[i][i]
+		s += w[k]
+	}
+	var v float64
+	for a, i := range items {
+		for b, j := range items {
+			v += w[a] / s * cov[i][j] * w[b] / s
+		}
+	}
+	return v
+}
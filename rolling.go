@@ -0,0 +1,169 @@
+package money
+
+/*
+The following functions are available
+
+Each returns a slice the same len() as its input, NaN until the first full
+window of w values, so that out[i] is the statistic of the window ending at
+i. Windows are updated in O(1) per step (Welford add and remove) and match
+the batch functions on each window. A window holding a NaN or infinite
+value is NaN, later windows are unaffected once it has left.
+
+RollingMean Mean of each window
+  RollingMean(x []float64, w int) []float64
+RollingSD Standard Deviation of each window (see SD)
+  RollingSD(x []float64, w int) []float64
+RollingSDs Standard Deviation of each window as a sample (see SDs)
+  RollingSDs(x []float64, w int) []float64
+RollingCov Covariance of each window (see Cov)
+  RollingCov(x, y []float64, w int) []float64
+RollingCorr Correlation of each window
+  RollingCorr(x, y []float64, w int) []float64
+RollingBeta slope of y on x over each window (see R)
+  RollingBeta(x, y []float64, w int) []float64
+(ts *TimeSeries) RollingMean, RollingSD the same on a TimeSeries
+*/
+
+import "math"
+
+// window running moments of the pairs (x, y) in a rolling window
+// cxy, cxx, cyy = sums of products of deviations from the means
+type window struct {
+	n             float64
+	mx, my        float64
+	cxx, cyy, cxy float64
+}
+
+// add a pair to the window
+func (m *window) add(x, y float64) {
+	m.n++
+	dx := x - m.mx
+	dy := y - m.my
+	m.mx += dx / m.n
+	m.my += dy / m.n
+	m.cxx += dx * (x - m.mx)
+	m.cyy += dy * (y - m.my)
+	m.cxy += dx * (y - m.my)
+}
+
+// remove a pair from the window
+func (m *window) remove(x, y float64) {
+	if m.n <= 1 {
+		*m = window{}
+		return
+	}
+	m.n--
+	mx := m.mx - (x-m.mx)/m.n
+	my := m.my - (y-m.my)/m.n
+	m.cxx -= (x - mx) * (x - m.mx)
+	m.cyy -= (y - my) * (y - m.my)
+	m.cxy -= (x - mx) * (y - m.my)
+	m.mx, m.my = mx, my
+}
+
+// rolling runs f over each window of w pairs
+func rolling(x, y []float64, w int, f func(m *window) float64) []float64 {
+	if w < 1 || len(x) != len(y) {
+		panic(NOOR)
+	}
+	out := make([]float64, len(x))
+	var m window
+	bad := 0 // NaN or infinite pairs in the window, kept out of m
+	for i := range x {
+		if finitePair(x[i], y[i]) {
+			m.add(x[i], y[i])
+		} else {
+			bad++
+		}
+		if i >= w {
+			if finitePair(x[i-w], y[i-w]) {
+				m.remove(x[i-w], y[i-w])
+			} else {
+				bad--
+			}
+		}
+		if i < w-1 || bad > 0 {
+			out[i] = math.NaN()
+			continue
+		}
+		out[i] = f(&m)
+	}
+	return out
+}
+
+// finitePair true if neither x nor y is NaN or infinite
+func finitePair(x, y float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0) && !math.IsNaN(y) && !math.IsInf(y, 0)
+}
+
+// RollingMean Mean of each window of w values
+func RollingMean(x []float64, w int) []float64 {
+	return rolling(x, x, w, func(m *window) float64 { return m.mx })
+}
+
+// RollingSD Standard Deviation of each window of w values
+// sd = sqrt(SIGMA ((a[i] - mean) ^ 2) / w)
+func RollingSD(x []float64, w int) []float64 {
+	return rolling(x, x, w, func(m *window) float64 {
+		return math.Sqrt(math.Max(m.cxx, 0) / m.n)
+	})
+}
+
+// RollingSDs Standard Deviation of each window of w values as a sample
+// sd = sqrt(SIGMA ((a[i] - mean) ^ 2) / (w - 1))
+func RollingSDs(x []float64, w int) []float64 {
+	if w < 2 {
+		panic(NOOR)
+	}
+	return rolling(x, x, w, func(m *window) float64 {
+		return math.Sqrt(math.Max(m.cxx, 0) / (m.n - 1))
+	})
+}
+
+// RollingCov Covariance of each window of w pairs
+// cov = SIGMA ((x[i] - mean x) * (y[i] - mean y)) / w
+func RollingCov(x, y []float64, w int) []float64 {
+	return rolling(x, y, w, func(m *window) float64 { return m.cxy / m.n })
+}
+
+// RollingCorr Correlation of each window of w pairs
+// corr = cov(x, y) / (sd(x) * sd(y)), NaN when either is constant
+func RollingCorr(x, y []float64, w int) []float64 {
+	return rolling(x, y, w, func(m *window) float64 {
+		d := math.Sqrt(m.cxx * m.cyy)
+		if d <= 0 {
+			return math.NaN()
+		}
+		return m.cxy / d
+	})
+}
+
+// RollingBeta slope of y on x over each window of w pairs
+// beta = cov(x, y) / var(x), ex. x market and y fund returns
+func RollingBeta(x, y []float64, w int) []float64 {
+	return rolling(x, y, w, func(m *window) float64 {
+		if m.cxx <= 0 {
+			return math.NaN()
+		}
+		return m.cxy / m.cxx
+	})
+}
+
+// RollingMean Mean of each window of w values, dated at the window end
+func (ts *TimeSeries) RollingMean(w int) *TimeSeries {
+	return ts.trim(RollingMean(ts.Values, w), w)
+}
+
+// RollingSD Standard Deviation of each window of w values, dated at the
+// window end
+func (ts *TimeSeries) RollingSD(w int) *TimeSeries {
+	return ts.trim(RollingSD(ts.Values, w), w)
+}
+
+// trim drops the w-1 leading values before the first full window
+func (ts *TimeSeries) trim(v []float64, w int) *TimeSeries {
+	if len(v) < w {
+		return &TimeSeries{}
+	}
+	return &TimeSeries{Times: ts.Times[w-1:], Values: v[w-1:]}
+}
@@ -0,0 +1,69 @@
+package money
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRolling(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	x := make([]float64, 200)
+	y := make([]float64, 200)
+	for i := range x {
+		x[i] = 0.001 + 0.02*rng.NormFloat64()
+		y[i] = 0.5*x[i] + 0.01*rng.NormFloat64()
+	}
+	corr := func(x, y []float64) float64 { return Cov(x, y) / (SD(x) * SD(y)) }
+	beta := func(x, y []float64) float64 {
+		_, b, _ := R(x, y)
+		return b
+	}
+	tests := []struct {
+		name    string
+		rolling func(x, y []float64, w int) []float64
+		batch   func(x, y []float64) float64
+	}{
+		{"RollingMean", func(x, _ []float64, w int) []float64 { return RollingMean(x, w) }, func(x, _ []float64) float64 { return Mean(x) }},
+		{"RollingSD", func(x, _ []float64, w int) []float64 { return RollingSD(x, w) }, func(x, _ []float64) float64 { return SD(x) }},
+		{"RollingSDs", func(x, _ []float64, w int) []float64 { return RollingSDs(x, w) }, func(x, _ []float64) float64 { return SDs(x) }},
+		{"RollingCov", RollingCov, Cov},
+		{"RollingCorr", RollingCorr, corr},
+		{"RollingBeta", RollingBeta, beta},
+	}
+	for _, tt := range tests {
+		for _, w := range []int{2, 30, 90} {
+			got := tt.rolling(x, y, w)
+			if len(got) != len(x) {
+				t.Fatalf("%s w %d len = %d, want %d", tt.name, w, len(got), len(x))
+			}
+			for i, v := range got {
+				if i < w-1 {
+					if !math.IsNaN(v) {
+						t.Errorf("%s w %d [%d] = %v, want NaN", tt.name, w, i, v)
+					}
+					continue
+				}
+				if want := tt.batch(x[i-w+1:i+1], y[i-w+1:i+1]); math.Abs(v-want) > 1e-9*math.Max(1, math.Abs(want)) {
+					t.Errorf("%s w %d [%d] = %v, batch %v", tt.name, w, i, v, want)
+				}
+			}
+		}
+	}
+}
+
+func TestRollingNaN(t *testing.T) {
+	x := []float64{1, 2, 3, math.NaN(), 5, 6, 7, math.Inf(1), 9, 10, 11}
+	want := []float64{math.NaN(), math.NaN(), 2, math.NaN(), math.NaN(), math.NaN(), 6, math.NaN(), math.NaN(), math.NaN(), 10}
+	got := RollingMean(x, 3)
+	for i := range want {
+		if math.IsNaN(want[i]) != math.IsNaN(got[i]) || !math.IsNaN(want[i]) && math.Abs(got[i]-want[i]) > 1e-12 {
+			t.Errorf("RollingMean [%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+	// a NaN in y alone spoils the pair
+	y := []float64{2, 4, math.NaN(), 8, 10, 12}
+	if got := RollingCov(x[:6], y, 2); !math.IsNaN(got[2]) || !math.IsNaN(got[3]) || !math.IsNaN(got[4]) || math.Abs(got[5]-0.5) > 1e-12 {
+		t.Errorf("RollingCov = %v", got)
+	}
+}
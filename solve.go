@@ -0,0 +1,336 @@
+package money
+
+import "math"
+
+// brent finds a root of f bracketed by [a, b] using Brent's method
+// (inverse quadratic interpolation, secant and bisection steps)
+// tol = absolute tolerance on the root
+// returns the root and false if [a, b] does not bracket a root or the
+// iteration limit is reached
+func brent(f func(float64) float64, a, b, tol float64) (float64, bool) {
+	const maxIter = 200
+	fa, fb := f(a), f(b)
+	if fa == 0 {
+		return a, true
+	}
+	if fb == 0 {
+		return b, true
+	}
+	if math.IsNaN(fa) || math.IsNaN(fb) || fa*fb > 0 {
+		return math.NaN(), false
+	}
+	if math.Abs(fa) < math.Abs(fb) {
+		a, b, fa, fb = b, a, fb, fa
+	}
+	c, fc := a, fa
+	d := b - a
+	bisected := true
+	for i := 0; i < maxIter; i++ {
+		if fb == 0 || math.Abs(b-a) < tol {
+			return b, true
+		}
+		var s float64
+		if fa != fc && fb != fc {
+			s = a*fb*fc/((fa-fb)*(fa-fc)) +
+				b*fa*fc/((fb-fa)*(fb-fc)) +
+				c*fa*fb/((fc-fa)*(fc-fb))
+		} else {
+			s = b - fb*(b-a)/(fb-fa)
+		}
+		lo, hi := (3*a+b)/4, b
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		if s < lo || s > hi ||
+			(bisected && math.Abs(s-b) >= math.Abs(b-c)/2) ||
+			(!bisected && math.Abs(s-b) >= math.Abs(c-d)/2) ||
+			(bisected && math.Abs(b-c) < tol) ||
+			(!bisected && math.Abs(c-d) < tol) {
+			s = (a + b) / 2
+			bisected = true
+		} else {
+			bisected = false
+		}
+		fs := f(s)
+		d, c, fc = c, b, fb
+		if fa*fs < 0 {
+			b, fb = s, fs
+		} else {
+			a, fa = s, fs
+		}
+		if math.Abs(fa) < math.Abs(fb) {
+			a, b, fa, fb = b, a, fb, fa
+		}
+	}
+	return b, false
+}
+
+// nelderMead minimises f from x0 with the Nelder-Mead simplex method
+// step = initial simplex offsets for each coordinate
+// tol = stop when the spread of f over the simplex falls below tol
+// returns the best point and its value, f may return +Inf to reject a point
+func nelderMead(f func([]float64) float64, x0, step []float64, tol float64, maxIter int) ([]float64, float64) {
+	n := len(x0)
+	pts := make([][]float64, n+1)
+	vals := make([]float64, n+1)
+	for i := range pts {
+		pts[i] = append([]float64(nil), x0...)
+		if i > 0 {
+			pts[i][i-1] += step[i-1]
+		}
+		vals[i] = f(pts[i])
+	}
+	point := func(c []float64, p []float64, t float64) []float64 {
+		x := make([]float64, n)
+		for j := range x {
+			x[j] = c[j] + t*(p[j]-c[j])
+		}
+		return x
+	}
+	for iter := 0; iter < maxIter; iter++ {
+		// order best to worst
+		for i := 1; i <= n; i++ {
+			for j := i; j > 0 && vals[j] < vals[j-1]; j-- {
+				vals[j], vals[j-1] = vals[j-1], vals[j]
+				pts[j], pts[j-1] = pts[j-1], pts[j]
+			}
+		}
+		if math.Abs(vals[n]-vals[0]) <= tol*(math.Abs(vals[0])+tol) {
+			break
+		}
+		c := make([]float64, n)
+		for i := 0; i < n; i++ {
+			for j := range c {
+				c[j] += pts[i][j] / float64(n)
+			}
+		}
+		xr := point(c, pts[n], -1)
+		fr := f(xr)
+		switch {
+		case fr < vals[0]:
+			xe := point(c, pts[n], -2)
+			if fe := f(xe); fe < fr {
+				pts[n], vals[n] = xe, fe
+			} else {
+				pts[n], vals[n] = xr, fr
+			}
+		case fr < vals[n-1]:
+			pts[n], vals[n] = xr, fr
+		default:
+			xc := point(c, pts[n], 0.5)
+			if fr < vals[n] {
+				xc = point(c, pts[n], -0.5)
+			}
+			if fc := f(xc); fc < math.Min(fr, vals[n]) {
+				pts[n], vals[n] = xc, fc
+				continue
+			}
+			for i := 1; i <= n; i++ {
+				pts[i] = point(pts[0], pts[i], 0.5)
+				vals[i] = f(pts[i])
+			}
+		}
+	}
+	best := 0
+	for i := range vals {
+		if vals[i] < vals[best] {
+			best = i
+		}
+	}
+	return pts[best], vals[best]
+}
+
+// quadprog solves the convex quadratic programme
+// min x' * p * x / 2 + q' * x subject to l <= a * x <= u
+// by the alternating direction method of multipliers (as OSQP), with the
+// step size adapted to balance the residuals, then polished by solving
+// the equations of the constraints found active
+// rows with l = u are equalities, bounds may be infinite
+// returns x and false if the residuals did not reach tolerance
+func quadprog(p [][]float64, q []float64, a [][]float64, l, u []float64) ([]float64, bool) {
+	const (
+		sigma   = 1e-6
+		alpha   = 1.6
+		eps     = 1e-10
+		maxIter = 20000
+	)
+	n, m := len(q), len(a)
+	at := func(v []float64) []float64 { // a' * v
+		r := make([]float64, n)
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				r[j] += a[i][j] * v[i]
+			}
+		}
+		return r
+	}
+	mul := func(mat [][]float64, v []float64) []float64 {
+		r := make([]float64, len(mat))
+		for i := range mat {
+			for j, x := range mat[i] {
+				r[i] += x * v[j]
+			}
+		}
+		return r
+	}
+	norm := func(v []float64) float64 {
+		var r float64
+		for _, x := range v {
+			r = math.Max(r, math.Abs(x))
+		}
+		return r
+	}
+	rho0 := 0.1
+	rho := make([]float64, m)
+	setRho := func() ([][]float64, bool) {
+		for i := range rho {
+			rho[i] = rho0
+			if l[i] == u[i] {
+				rho[i] = 1e3 * rho0
+			}
+		}
+		k := make([][]float64, n)
+		for i := range k {
+			k[i] = append([]float64(nil), p[i]...)
+			k[i][i] += sigma
+		}
+		for r := 0; r < m; r++ {
+			for i := 0; i < n; i++ {
+				if a[r][i] == 0 {
+					continue
+				}
+				for j := 0; j < n; j++ {
+					k[i][j] += rho[r] * a[r][i] * a[r][j]
+				}
+			}
+		}
+		return invert(k)
+	}
+	kinv, ok := setRho()
+	if !ok {
+		return nil, false
+	}
+	x := make([]float64, n)
+	z := make([]float64, m)
+	y := make([]float64, m)
+	rhs := make([]float64, n)
+	w := make([]float64, m)
+	converged := false
+	for it := 1; it <= maxIter; it++ {
+		for i := range w {
+			w[i] = rho[i]*z[i] - y[i]
+		}
+		atw := at(w)
+		for i := range rhs {
+			rhs[i] = sigma*x[i] - q[i] + atw[i]
+		}
+		xt := mul(kinv, rhs)
+		zt := mul(a, xt)
+		for i := range x {
+			x[i] = alpha*xt[i] + (1-alpha)*x[i]
+		}
+		for i := range z {
+			zr := alpha*zt[i] + (1-alpha)*z[i]
+			zn := math.Max(l[i], math.Min(u[i], zr+y[i]/rho[i]))
+			y[i] += rho[i] * (zr - zn)
+			z[i] = zn
+		}
+		if it%25 != 0 {
+			continue
+		}
+		ax := mul(a, x)
+		px := mul(p, x)
+		aty := at(y)
+		rp := make([]float64, m)
+		for i := range rp {
+			rp[i] = ax[i] - z[i]
+		}
+		rd := make([]float64, n)
+		for i := range rd {
+			rd[i] = px[i] + q[i] + aty[i]
+		}
+		np := math.Max(norm(ax), norm(z))
+		nd := math.Max(norm(px), math.Max(norm(aty), norm(q)))
+		if norm(rp) <= eps*(1+np) && norm(rd) <= eps*(1+nd) {
+			converged = true
+			break
+		}
+		// adapt the step size when the residuals are out of balance
+		scale := math.Sqrt((norm(rp) / math.Max(np, 1e-30)) / (norm(rd) / math.Max(nd, 1e-30)))
+		if r := rho0 * scale; !math.IsNaN(r) && (r > 5*rho0 || r < rho0/5) {
+			rho0 = math.Max(1e-6, math.Min(r, 1e6))
+			if kinv, ok = setRho(); !ok {
+				return nil, false
+			}
+		}
+	}
+	if xp, ok := polish(p, q, a, l, u, z, y); ok {
+		return xp, true
+	}
+	return x, converged
+}
+
+// polish solves the equality constrained problem of the active
+// constraints of an ADMM solution, false if that is singular or the
+// result breaks a constraint or a multiplier sign
+func polish(p [][]float64, q []float64, a [][]float64, l, u, z, y []float64) ([]float64, bool) {
+	const tol = 1e-9
+	n := len(q)
+	var act []int
+	var b []float64
+	for i := range a {
+		switch {
+		case l[i] == u[i], z[i]-l[i] < -y[i]:
+			act = append(act, i)
+			b = append(b, l[i])
+		case u[i]-z[i] < y[i]:
+			act = append(act, i)
+			b = append(b, u[i])
+		}
+	}
+	k := len(act)
+	kkt := make([][]float64, n+k)
+	rhs := make([]float64, n+k)
+	for i := 0; i < n; i++ {
+		kkt[i] = make([]float64, n+k)
+		copy(kkt[i], p[i])
+		rhs[i] = -q[i]
+	}
+	for r, i := range act {
+		kkt[n+r] = make([]float64, n+k)
+		copy(kkt[n+r], a[i])
+		for j := 0; j < n; j++ {
+			kkt[j][n+r] = a[i][j]
+		}
+		rhs[n+r] = b[r]
+	}
+	inv, ok := invert(kkt)
+	if !ok {
+		return nil, false
+	}
+	sol := make([]float64, n+k)
+	for i := range sol {
+		for j := range rhs {
+			sol[i] += inv[i][j] * rhs[j]
+		}
+	}
+	x := sol[:n]
+	for i := range a {
+		var ax float64
+		for j := range x {
+			ax += a[i][j] * x[j]
+		}
+		if ax < l[i]-tol*(1+math.Abs(l[i])) || ax > u[i]+tol*(1+math.Abs(u[i])) {
+			return nil, false
+		}
+	}
+	for r, i := range act {
+		if l[i] == u[i] {
+			continue
+		}
+		if (b[r] == l[i] && sol[n+r] > tol) || (b[r] == u[i] && sol[n+r] < -tol) {
+			return nil, false
+		}
+	}
+	return x, true
+}
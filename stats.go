@@ -0,0 +1,267 @@
+package money
+
+/*
+The following functions are available
+
+Median middle value
+  Median(a []float64) float64
+Quantile value below which a fraction p of a lies
+  Quantile(a []float64, p float64, m QuantileMethod) float64
+Quantiles several quantiles sorting a once
+  Quantiles(a, ps []float64, m QuantileMethod) []float64
+Skewness, Kurtosis population skewness and excess kurtosis
+  Skewness(a []float64) float64
+  Kurtosis(a []float64) float64
+SkewnessSample, KurtosisSample the same adjusted for sample bias
+  SkewnessSample(a []float64) float64
+  KurtosisSample(a []float64) float64
+WeightedMean Mean with weights
+  WeightedMean(a, w []float64) float64
+WeightedVariance Variance about the WeightedMean
+  WeightedVariance(a, w []float64) float64
+Mode most frequent values
+  Mode(a []float64) []float64
+Histogram counts of a in equal width bins
+  Histogram(a []float64, bins int) (edges []float64, counts []int)
+
+NaN values are missing: the order statistics (Median, Quantile, Quantiles,
+Mode and Histogram) skip them and panic if none are left, the moments
+(Skewness, Kurtosis and the weighted functions) return NaN as Mean does.
+*/
+
+import (
+	"math"
+	"sort"
+)
+
+// QuantileMethod is how a quantile between two sorted values is taken
+// h is the position of the quantile in the sorted values (0 first)
+type QuantileMethod int
+
+const (
+	Linear   QuantileMethod = iota // h = (n - 1) * p, interpolated (Excel PERCENTILE.INC)
+	Lower                          // the value at or below h
+	Higher                         // the value at or above h
+	Nearest                        // the value nearest h, ties to even
+	Midpoint                       // half way between Lower and Higher
+	Weibull                        // h = (n + 1) * p - 1, interpolated (Excel PERCENTILE.EXC)
+	Hazen                          // h = n * p - 1/2, interpolated
+)
+
+// sorted copy of a without its NaN values
+func sorted(a []float64) []float64 {
+	s := make([]float64, 0, len(a))
+	for _, v := range a {
+		if !math.IsNaN(v) {
+			s = append(s, v)
+		}
+	}
+	if len(s) == 0 {
+		panic(NOOR)
+	}
+	sort.Float64s(s)
+	return s
+}
+
+// Median middle value of a, the mean of the two middle values when
+// there is an even number of them, NaN values skipped
+func Median(a []float64) float64 {
+	return Quantile(a, 0.5, Linear)
+}
+
+// Quantile value below which a fraction p of a lies
+// p = 0 to 1 ex. 0.25 for the lower quartile, 0.05 for a 95% VaR
+// m = interpolation between values, see QuantileMethod
+// positions outside a are held at its smallest or largest value
+// NaN values (missing) are skipped
+func Quantile(a []float64, p float64, m QuantileMethod) float64 {
+	return quantile(sorted(a), p, m)
+}
+
+// Quantiles each quantile in ps, see Quantile
+func Quantiles(a, ps []float64, m QuantileMethod) []float64 {
+	s := sorted(a)
+	q := make([]float64, len(ps))
+	for i, p := range ps {
+		q[i] = quantile(s, p, m)
+	}
+	return q
+}
+
+// quantile p quantile of the sorted values s
+func quantile(s []float64, p float64, m QuantileMethod) float64 {
+	if p < 0 || p > 1 || math.IsNaN(p) {
+		panic(NOOR)
+	}
+	n := float64(len(s))
+	var h float64
+	switch m {
+	case Weibull:
+		h = (n+1)*p - 1
+	case Hazen:
+		h = n*p - 0.5
+	default:
+		h = (n - 1) * p
+	}
+	h = math.Max(0, math.Min(h, n-1))
+	lo, hi := math.Floor(h), math.Ceil(h)
+	switch m {
+	case Lower:
+		return s[int(lo)]
+	case Higher:
+		return s[int(hi)]
+	case Nearest:
+		return s[int(math.RoundToEven(h))]
+	case Midpoint:
+		return (s[int(lo)] + s[int(hi)]) / 2
+	}
+	return s[int(lo)] + (h-lo)*(s[int(hi)]-s[int(lo)])
+}
+
+// moments central moments m2, m3, m4 of a (divided by n)
+func moments(a []float64) (m2, m3, m4 float64) {
+	mean := Mean(a)
+	for _, v := range a {
+		d := v - mean
+		d2 := d * d
+		m2 += d2
+		m3 += d2 * d
+		m4 += d2 * d2
+	}
+	n := float64(len(a))
+	return m2 / n, m3 / n, m4 / n
+}
+
+// Skewness population skewness
+// g1 = m3 / m2^(3/2)
+// m2, m3 = SIGMA ((a[i] - mean) ^ 2 or 3) / n
+func Skewness(a []float64) float64 {
+	m2, m3, _ := moments(a)
+	return m3 / math.Pow(m2, 1.5)
+}
+
+// SkewnessSample skewness adjusted for sample bias (Excel SKEW)
+// G1 = g1 * sqrt(n * (n - 1)) / (n - 2)
+func SkewnessSample(a []float64) float64 {
+	n := float64(len(a))
+	if n < 3 {
+		panic(NOOR)
+	}
+	return Skewness(a) * math.Sqrt(n*(n-1)) / (n - 2)
+}
+
+// Kurtosis population excess kurtosis, 0 for a normal distribution
+// g2 = m4 / m2^2 - 3
+// m2, m4 = SIGMA ((a[i] - mean) ^ 2 or 4) / n
+func Kurtosis(a []float64) float64 {
+	m2, _, m4 := moments(a)
+	return m4/(m2*m2) - 3
+}
+
+// KurtosisSample excess kurtosis adjusted for sample bias (Excel KURT)
+// G2 = ((n + 1) * g2 + 6) * (n - 1) / ((n - 2) * (n - 3))
+func KurtosisSample(a []float64) float64 {
+	n := float64(len(a))
+	if n < 4 {
+		panic(NOOR)
+	}
+	return ((n+1)*Kurtosis(a) + 6) * (n - 1) / ((n - 2) * (n - 3))
+}
+
+// WeightedMean Average with weights
+// mean = SIGMA (w[i] * a[i]) / SIGMA w[i]
+// w = weights ex. position sizes, need not sum to 1
+// a and w must correspond, be the same len()
+func WeightedMean(a, w []float64) float64 {
+	if len(a) == 0 || len(a) != len(w) {
+		panic(NOOR)
+	}
+	var sum, sumW float64
+	for i, v := range a {
+		sum += w[i] * v
+		sumW += w[i]
+	}
+	if sumW == 0 {
+		panic(DBZ)
+	}
+	return sum / sumW
+}
+
+// WeightedVariance Variance about the WeightedMean
+// var = SIGMA (w[i] * (a[i] - mean) ^ 2) / SIGMA w[i]
+// the population form, equal weights give SD(a)^2
+func WeightedVariance(a, w []float64) float64 {
+	mean := WeightedMean(a, w)
+	var sum, sumW float64
+	for i, v := range a {
+		sum += w[i] * (v - mean) * (v - mean)
+		sumW += w[i]
+	}
+	return sum / sumW
+}
+
+// Mode most frequent values of a in ascending order, several when tied
+// NaN values (missing) are skipped
+func Mode(a []float64) []float64 {
+	s := sorted(a)
+	var mode []float64
+	best := 0
+	for i := 0; i < len(s); {
+		j := i
+		for j < len(s) && s[j] == s[i] {
+			j++
+		}
+		switch {
+		case j-i > best:
+			best = j - i
+			mode = append(mode[:0], s[i])
+		case j-i == best:
+			mode = append(mode, s[i])
+		}
+		i = j
+	}
+	return mode
+}
+
+// Histogram counts of a in bins of equal width from its smallest to its
+// largest value
+// edges = bins + 1 bin boundaries, bin i holds edges[i] <= v < edges[i+1]
+// and the last bin includes its upper edge
+// NaN (missing) and infinite values are skipped
+func Histogram(a []float64, bins int) (edges []float64, counts []int) {
+	if bins < 1 {
+		panic(NOOR)
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range a {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if lo > hi {
+		panic(NOOR)
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+	width := (hi - lo) / float64(bins)
+	edges = make([]float64, bins+1)
+	for i := range edges {
+		edges[i] = lo + float64(i)*width
+	}
+	edges[bins] = hi
+	counts = make([]int, bins)
+	for _, v := range a {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		b := int((v - lo) / width)
+		if b >= bins {
+			b = bins - 1
+		}
+		counts[b]++
+	}
+	return edges, counts
+}
@@ -0,0 +1,50 @@
+package money
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuantileNaN(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name string
+		a    []float64
+		p    float64
+		m    QuantileMethod
+		want float64
+	}{
+		{"median", []float64{nan, 1, 2, 3}, 0.5, Linear, 2},
+		{"median even", []float64{4, nan, 1, 2, nan, 3}, 0.5, Linear, 2.5},
+		{"smallest", []float64{nan, 1, 2, 3}, 0, Linear, 1},
+		{"largest", []float64{nan, 1, 2, 3}, 1, Linear, 3},
+		{"lower quartile", []float64{5, nan, 1, 3}, 0.25, Lower, 1},
+		{"Weibull", []float64{nan, 1, 2, 3, 4, nan}, 0.5, Weibull, 2.5},
+	}
+	for _, tt := range tests {
+		if got := Quantile(tt.a, tt.p, tt.m); got != tt.want {
+			t.Errorf("%s: Quantile = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if got := Median([]float64{nan, 1, 2, 3}); got != 2 {
+		t.Errorf("Median = %v, want 2", got)
+	}
+	got := Quantiles([]float64{3, nan, 1, 2}, []float64{0, 0.5, 1}, Linear)
+	for i, want := range []float64{1, 2, 3} {
+		if got[i] != want {
+			t.Errorf("Quantiles[%d] = %v, want %v", i, got[i], want)
+		}
+	}
+	if got := Mode([]float64{nan, nan, nan, 1, 2, 2}); len(got) != 1 || got[0] != 2 {
+		t.Errorf("Mode = %v, want [2]", got)
+	}
+}
+
+func TestQuantileAllNaN(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Median of only NaN did not panic")
+		}
+	}()
+	Median([]float64{math.NaN(), math.NaN()})
+}
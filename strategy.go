@@ -0,0 +1,301 @@
+package money
+
+/*
+The following types and functions are available
+
+Leg an option or stock position within a Strategy
+Strategy option and stock legs priced with BSM on one underlying
+  Strategy{S, R, Q, Legs}
+Straddle, Strangle, VerticalSpread, IronCondor, CoveredCall common strategies
+Grid evenly spaced underlying prices
+  Grid(lo, hi float64, n int) []float64
+Premium net premium paid (negative for a credit)
+  (st *Strategy) Premium() float64
+Horizon time of the first option expiry
+  (st *Strategy) Horizon() float64
+Payoff value of the legs at the horizon over a price grid
+  (st *Strategy) Payoff(grid []float64) []float64
+PnL payoff less premium over a price grid
+  (st *Strategy) PnL(grid []float64) []float64
+Breakevens prices at which the P&L at the horizon is zero
+  (st *Strategy) Breakevens(grid []float64) []float64
+MaxProfit, MaxLoss extremes of the P&L (math.Inf when unbounded)
+  (st *Strategy) MaxProfit(grid []float64) float64
+  (st *Strategy) MaxLoss(grid []float64) float64
+Greeks aggregated BSM Greeks of the legs
+  (st *Strategy) Greeks() Greeks
+Table, CSV P&L over a price grid
+  (st *Strategy) Table(w io.Writer, grid []float64) error
+  (st *Strategy) CSV(w io.Writer, grid []float64) error
+*/
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"text/tabwriter"
+)
+
+// Leg is one position of a Strategy
+// Stock = a stock leg (Type, Strike, Expiry and Vol are ignored)
+// Type = Call or Put
+// Qty = quantity, positive for long and negative for short
+// Strike = option strike price
+// Expiry = time to expire in years
+// Vol = volatility used to price the option
+type Leg struct {
+	Stock  bool
+	Type   OptionType
+	Qty    float64
+	Strike float64
+	Expiry float64
+	Vol    float64
+}
+
+// Strategy is a set of legs on one underlying
+// S = Spot price
+// R = risk free rate
+// Q = dividend yield
+type Strategy struct {
+	S, R, Q float64
+	Legs    []Leg
+}
+
+// Straddle long call and put at strike k (qty < 0 for a short straddle)
+func Straddle(k, t, v, qty float64) []Leg {
+	return []Leg{
+		{Type: Call, Qty: qty, Strike: k, Expiry: t, Vol: v},
+		{Type: Put, Qty: qty, Strike: k, Expiry: t, Vol: v},
+	}
+}
+
+// Strangle long put at kp and call at kc (qty < 0 for a short strangle)
+func Strangle(kp, kc, t, v, qty float64) []Leg {
+	return []Leg{
+		{Type: Put, Qty: qty, Strike: kp, Expiry: t, Vol: v},
+		{Type: Call, Qty: qty, Strike: kc, Expiry: t, Vol: v},
+	}
+}
+
+// VerticalSpread long o at k1, short o at k2
+// ex. a bull call spread is VerticalSpread(Call, 95, 105, ...)
+func VerticalSpread(o OptionType, k1, k2, t, v, qty float64) []Leg {
+	return []Leg{
+		{Type: o, Qty: qty, Strike: k1, Expiry: t, Vol: v},
+		{Type: o, Qty: -qty, Strike: k2, Expiry: t, Vol: v},
+	}
+}
+
+// IronCondor short put spread k2/k1 and short call spread k3/k4
+// k1 < k2 < k3 < k4, long the wings k1 and k4
+func IronCondor(k1, k2, k3, k4, t, v, qty float64) []Leg {
+	return []Leg{
+		{Type: Put, Qty: qty, Strike: k1, Expiry: t, Vol: v},
+		{Type: Put, Qty: -qty, Strike: k2, Expiry: t, Vol: v},
+		{Type: Call, Qty: -qty, Strike: k3, Expiry: t, Vol: v},
+		{Type: Call, Qty: qty, Strike: k4, Expiry: t, Vol: v},
+	}
+}
+
+// CoveredCall long qty shares and short qty calls at k
+func CoveredCall(k, t, v, qty float64) []Leg {
+	return []Leg{
+		{Stock: true, Qty: qty},
+		{Type: Call, Qty: -qty, Strike: k, Expiry: t, Vol: v},
+	}
+}
+
+// Grid n evenly spaced prices from lo to hi, lo may be 0
+func Grid(lo, hi float64, n int) []float64 {
+	if n < 2 || lo < 0 || hi <= lo {
+		panic(NOOR)
+	}
+	g := make([]float64, n)
+	for i := range g {
+		g[i] = lo + (hi-lo)*float64(i)/float64(n-1)
+	}
+	return g
+}
+
+// Premium net premium paid to enter the strategy
+// premium = SIGMA qty * BSM (s * qty for a stock leg)
+// negative for a net credit
+func (st *Strategy) Premium() float64 {
+	return st.value(st.S, 0)
+}
+
+// Horizon time in years of the first option expiry, at which the
+// payoff is measured (zero for stock only strategies)
+func (st *Strategy) Horizon() float64 {
+	h := math.Inf(1)
+	for _, l := range st.Legs {
+		if !l.Stock && l.Expiry < h {
+			h = l.Expiry
+		}
+	}
+	if math.IsInf(h, 1) {
+		return 0
+	}
+	return h
+}
+
+// value of the legs at price s once elapsed years have passed, options
+// still to expire are priced with BSM, at s = 0 with their bound
+func (st *Strategy) value(s, elapsed float64) float64 {
+	var sum float64
+	for _, l := range st.Legs {
+		if l.Stock {
+			sum += l.Qty * s
+			continue
+		}
+		tau := math.Max(l.Expiry-elapsed, 0)
+		if s == 0 { // worthless stock: a call is worth 0, a put its discounted strike
+			if l.Type == Put {
+				sum += l.Qty * l.Strike * math.Exp(-st.R*tau)
+			}
+			continue
+		}
+		sum += l.Qty * BSM(s, l.Strike, tau, st.R, st.Q, l.Vol, l.Type)
+	}
+	return sum
+}
+
+// Payoff value of the legs at the horizon for each price in grid
+// options expiring later than the horizon keep their BSM time value
+func (st *Strategy) Payoff(grid []float64) []float64 {
+	h := st.Horizon()
+	p := make([]float64, len(grid))
+	for i, s := range grid {
+		p[i] = st.value(s, h)
+	}
+	return p
+}
+
+// PnL profit or loss at the horizon for each price in grid
+// pnl = payoff - premium (financing of the premium is ignored)
+func (st *Strategy) PnL(grid []float64) []float64 {
+	prem := st.Premium()
+	p := st.Payoff(grid)
+	for i := range p {
+		p[i] -= prem
+	}
+	return p
+}
+
+// Breakevens prices within grid at which the P&L at the horizon is zero,
+// found by linear interpolation between grid points
+func (st *Strategy) Breakevens(grid []float64) []float64 {
+	pnl := st.PnL(grid)
+	var be []float64
+	for i := range pnl {
+		if pnl[i] == 0 {
+			be = append(be, grid[i])
+			continue
+		}
+		if i > 0 && pnl[i-1] != 0 && (pnl[i-1] < 0) != (pnl[i] < 0) {
+			w := pnl[i-1] / (pnl[i-1] - pnl[i])
+			be = append(be, grid[i-1]+w*(grid[i]-grid[i-1]))
+		}
+	}
+	return be
+}
+
+// MaxProfit greatest P&L over grid, +Inf if the P&L keeps rising as the
+// price rises without limit
+func (st *Strategy) MaxProfit(grid []float64) float64 {
+	if st.slope() > 0 {
+		return math.Inf(1)
+	}
+	max := math.Inf(-1)
+	for _, p := range st.PnL(grid) {
+		max = math.Max(max, p)
+	}
+	return max
+}
+
+// MaxLoss greatest loss over grid as a negative P&L, -Inf if the P&L keeps
+// falling as the price rises without limit
+func (st *Strategy) MaxLoss(grid []float64) float64 {
+	if st.slope() < 0 {
+		return math.Inf(-1)
+	}
+	min := math.Inf(1)
+	for _, p := range st.PnL(grid) {
+		min = math.Min(min, p)
+	}
+	return min
+}
+
+// slope of the payoff at the horizon for a price rising without limit:
+// stock and calls expiring at the horizon count fully, later calls
+// tend to a delta of e^(-q * remaining)
+func (st *Strategy) slope() float64 {
+	h := st.Horizon()
+	var d float64
+	for _, l := range st.Legs {
+		switch {
+		case l.Stock:
+			d += l.Qty
+		case l.Type == Call:
+			d += l.Qty * math.Exp(-st.Q*(l.Expiry-h))
+		}
+	}
+	if math.Abs(d) < 1e-12 {
+		return 0
+	}
+	return d
+}
+
+// Greeks aggregated BSM Greeks today
+// SIGMA qty * BSMGreeks, a stock leg adds qty to Delta
+func (st *Strategy) Greeks() Greeks {
+	var g Greeks
+	for _, l := range st.Legs {
+		if l.Stock {
+			g.Delta += l.Qty
+			continue
+		}
+		lg := BSMGreeks(st.S, l.Strike, l.Expiry, st.R, st.Q, l.Vol, l.Type)
+		g.Delta += l.Qty * lg.Delta
+		g.Gamma += l.Qty * lg.Gamma
+		g.Vega += l.Qty * lg.Vega
+		g.Theta += l.Qty * lg.Theta
+		g.Rho += l.Qty * lg.Rho
+		g.Phi += l.Qty * lg.Phi
+		g.Vanna += l.Qty * lg.Vanna
+		g.Volga += l.Qty * lg.Volga
+		g.Charm += l.Qty * lg.Charm
+	}
+	return g
+}
+
+// Table writes the payoff and P&L over grid as aligned text columns
+func (st *Strategy) Table(w io.Writer, grid []float64) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "price\tpayoff\tpnl\t\n")
+	pay := st.Payoff(grid)
+	prem := st.Premium()
+	for i, s := range grid {
+		fmt.Fprintf(tw, "%.2f\t%.2f\t%.2f\t\n", s, pay[i], pay[i]-prem)
+	}
+	return tw.Flush()
+}
+
+// CSV writes the payoff and P&L over grid as comma separated values
+func (st *Strategy) CSV(w io.Writer, grid []float64) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"price", "payoff", "pnl"})
+	pay := st.Payoff(grid)
+	prem := st.Premium()
+	for i, s := range grid {
+		cw.Write([]string{
+			strconv.FormatFloat(s, 'f', -1, 64),
+			strconv.FormatFloat(pay[i], 'f', -1, 64),
+			strconv.FormatFloat(pay[i]-prem, 'f', -1, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,307 @@
+package money
+
+/*
+The following types and functions are available
+
+Ledger tax lots of each symbol held and the gains realised from them
+  NewLedger(m LotMethod) *Ledger
+Buy opens a lot
+  (l *Ledger) Buy(symbol string, date time.Time, qty float64, cost *Money) int
+Sell relieves lots by the Method, or the lots given, realising gains
+  (l *Ledger) Sell(symbol string, date time.Time, qty float64, proceeds *Money, ids ...int) ([]Gain, error)
+Split multiplies the shares of every lot keeping its cost and date
+  (l *Ledger) Split(symbol string, ratio float64)
+Transfer moves shares with their cost and dates to another Ledger
+  (l *Ledger) Transfer(symbol string, qty float64, to *Ledger, ids ...int) error
+Lots open lots of a symbol
+  (l *Ledger) Lots(symbol string) []Lot
+Realised short and long term gains realised to date
+  (l *Ledger) Realised() (short, long Money)
+*/
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// LotMethod is the order in which lots are relieved on a sale
+type LotMethod int
+
+const (
+	FIFO        LotMethod = iota // first in, first out
+	LIFO                         // last in, first out
+	HIFO                         // highest unit cost first
+	AverageCost                  // every lot at the average unit cost, first in first out
+	SpecificLot                  // only the lots named on each sale
+)
+
+// lotEps is the share quantity treated as zero
+const lotEps = 1e-9
+
+// Lot shares of one symbol bought together
+// Cost = remaining total cost of the Quantity held
+type Lot struct {
+	ID       int
+	Symbol   string
+	Acquired time.Time
+	Quantity float64
+	Cost     Money
+}
+
+// UnitCost cost per share
+func (l *Lot) UnitCost() float64 {
+	return l.Cost.Get() / l.Quantity
+}
+
+// Gain realised on the sale of shares from one lot
+// Gain = Proceeds - Cost
+// Days = holding period in calendar days
+// LongTerm = held more than one year (sold after the anniversary of
+// Acquired)
+type Gain struct {
+	Symbol         string
+	LotID          int
+	Acquired, Sold time.Time
+	Quantity       float64
+	Proceeds, Cost Money
+	Gain           Money
+	Days           int
+	LongTerm       bool
+}
+
+// Ledger tax lots by symbol and the gains realised from them
+// Method = order lots are relieved when a sale names none
+// Gains = every gain realised, in order of sale
+// the zero value is an empty FIFO Ledger ready to use
+type Ledger struct {
+	Method LotMethod
+	Gains  []Gain
+	lots   map[string][]*Lot
+	nextID int
+}
+
+// NewLedger empty Ledger relieving lots by m
+func NewLedger(m LotMethod) *Ledger {
+	return &Ledger{Method: m, lots: map[string][]*Lot{}}
+}
+
+// Buy opens a lot of qty shares bought on date for a total cost
+// returns the lot ID, used to name lots on a sale
+func (l *Ledger) Buy(symbol string, date time.Time, qty float64, cost *Money) int {
+	if qty <= 0 {
+		panic(NOOR)
+	}
+	if l.lots == nil {
+		l.lots = map[string][]*Lot{}
+	}
+	l.nextID++
+	l.lots[symbol] = append(l.lots[symbol], &Lot{
+		ID: l.nextID, Symbol: symbol, Acquired: date, Quantity: qty, Cost: *cost,
+	})
+	return l.nextID
+}
+
+// Sell sells qty shares on date for total proceeds, relieving the lots
+// ids in the order given, or by Method when none are given
+// proceeds are shared between the lots in proportion to their shares and
+// each lot's cost relieved in proportion to the shares it has left, the
+// last share of a lot carrying its remaining cost so that no cent is lost
+// returns the gains realised, also appended to Gains, or LOTQTY or LOTID
+// (a lot not held or named twice) leaving the lots unchanged
+func (l *Ledger) Sell(symbol string, date time.Time, qty float64, proceeds *Money, ids ...int) ([]Gain, error) {
+	pieces, err := l.relieve(symbol, qty, ids)
+	if err != nil {
+		return nil, err
+	}
+	left := *proceeds
+	gains := make([]Gain, len(pieces))
+	for i, p := range pieces {
+		g := Gain{
+			Symbol:   symbol,
+			LotID:    p.ID,
+			Acquired: p.Acquired,
+			Sold:     date,
+			Quantity: p.Quantity,
+			Cost:     p.Cost,
+			Days:     int(days(p.Acquired, date)),
+			LongTerm: date.After(p.Acquired.AddDate(1, 0, 0)),
+		}
+		if i == len(pieces)-1 {
+			g.Proceeds = left
+		} else {
+			g.Proceeds.Setf(proceeds.Get() * p.Quantity / qty)
+			left.Sub(&g.Proceeds)
+		}
+		g.Gain = g.Proceeds
+		g.Gain.Sub(&g.Cost)
+		gains[i] = g
+	}
+	l.Gains = append(l.Gains, gains...)
+	return gains, nil
+}
+
+// Split multiplies the shares of every lot of symbol by ratio ex. 2 for a
+// 2 for 1 split, 0.1 for a 1 for 10 reverse split, leaving each lot's
+// cost and acquisition date unchanged
+func (l *Ledger) Split(symbol string, ratio float64) {
+	if ratio <= 0 {
+		panic(NOOR)
+	}
+	for _, lot := range l.lots[symbol] {
+		lot.Quantity *= ratio
+	}
+}
+
+// Transfer moves qty shares of symbol to another Ledger ex. between
+// accounts, relieving lots as Sell and opening them in to with their
+// cost and acquisition dates
+func (l *Ledger) Transfer(symbol string, qty float64, to *Ledger, ids ...int) error {
+	pieces, err := l.relieve(symbol, qty, ids)
+	if err != nil {
+		return err
+	}
+	for _, p := range pieces {
+		to.Buy(symbol, p.Acquired, p.Quantity, &p.Cost)
+	}
+	return nil
+}
+
+// Lots open lots of symbol in the order they would be relieved
+func (l *Ledger) Lots(symbol string) []Lot {
+	lots := l.ordered(symbol)
+	out := make([]Lot, len(lots))
+	for i, lot := range lots {
+		out[i] = *lot
+	}
+	return out
+}
+
+// Realised totals of the gains realised to date, short and long term
+func (l *Ledger) Realised() (short, long Money) {
+	for i := range l.Gains {
+		if l.Gains[i].LongTerm {
+			long.Add(&l.Gains[i].Gain)
+		} else {
+			short.Add(&l.Gains[i].Gain)
+		}
+	}
+	return short, long
+}
+
+// ordered lots of symbol in the order of Method
+func (l *Ledger) ordered(symbol string) []*Lot {
+	lots := append([]*Lot(nil), l.lots[symbol]...)
+	sort.SliceStable(lots, func(i, j int) bool {
+		a, b := lots[i], lots[j]
+		switch l.Method {
+		case LIFO:
+			if !a.Acquired.Equal(b.Acquired) {
+				return a.Acquired.After(b.Acquired)
+			}
+			return a.ID > b.ID
+		case HIFO:
+			if ua, ub := a.UnitCost(), b.UnitCost(); ua != ub {
+				return ua > ub
+			}
+		}
+		if !a.Acquired.Equal(b.Acquired) {
+			return a.Acquired.Before(b.Acquired)
+		}
+		return a.ID < b.ID
+	})
+	return lots
+}
+
+// average sets every lot of symbol to the average unit cost, the last
+// lot taking the remainder so the total cost is unchanged
+func (l *Ledger) average(symbol string) {
+	lots := l.lots[symbol]
+	var total Money
+	var qty float64
+	for _, lot := range lots {
+		total.Add(&lot.Cost)
+		qty += lot.Quantity
+	}
+	left := total
+	for i, lot := range lots {
+		if i == len(lots)-1 {
+			lot.Cost = left
+			break
+		}
+		lot.Cost.Setf(total.Get() * lot.Quantity / qty)
+		left.Sub(&lot.Cost)
+	}
+}
+
+// relieve removes qty shares of symbol from the lots ids, or the lots in
+// Method order, returning the pieces removed with their share of cost
+// a lot named twice returns LOTID
+func (l *Ledger) relieve(symbol string, qty float64, ids []int) ([]Lot, error) {
+	if qty <= 0 {
+		panic(NOOR)
+	}
+	var lots []*Lot
+	if len(ids) > 0 {
+		named := map[int]bool{}
+		for _, id := range ids {
+			if named[id] {
+				return nil, errors.New(LOTID + symbol)
+			}
+			named[id] = true
+			var found *Lot
+			for _, lot := range l.lots[symbol] {
+				if lot.ID == id {
+					found = lot
+				}
+			}
+			if found == nil {
+				return nil, errors.New(LOTID + symbol)
+			}
+			lots = append(lots, found)
+		}
+	} else {
+		if l.Method == SpecificLot {
+			return nil, errors.New(LOTID + symbol)
+		}
+		lots = l.ordered(symbol)
+	}
+	var held float64
+	for _, lot := range lots {
+		held += lot.Quantity
+	}
+	if qty > held+lotEps {
+		return nil, errors.New(LOTQTY + symbol)
+	}
+	if len(ids) == 0 && l.Method == AverageCost {
+		l.average(symbol)
+	}
+	var pieces []Lot
+	left := qty
+	for _, lot := range lots {
+		if left <= lotEps {
+			break
+		}
+		p := Lot{ID: lot.ID, Symbol: symbol, Acquired: lot.Acquired}
+		if left >= lot.Quantity-lotEps {
+			p.Quantity, p.Cost = lot.Quantity, lot.Cost
+			lot.Quantity = 0
+			lot.Cost.Set(0)
+		} else {
+			p.Quantity = left
+			p.Cost.Setf(lot.Cost.Get() * left / lot.Quantity)
+			lot.Quantity -= left
+			lot.Cost.Sub(&p.Cost)
+		}
+		left -= p.Quantity
+		pieces = append(pieces, p)
+	}
+	open := l.lots[symbol][:0]
+	for _, lot := range l.lots[symbol] {
+		if lot.Quantity > lotEps {
+			open = append(open, lot)
+		}
+	}
+	l.lots[symbol] = open
+	return pieces, nil
+}
@@ -0,0 +1,33 @@
+package money
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLedgerZeroValue(t *testing.T) {
+	var l Ledger
+	var cost, proceeds Money
+	cost.Setf(1000)
+	proceeds.Setf(600)
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	l.Buy("ABC", day, 10, &cost)
+	gains, err := l.Sell("ABC", day.AddDate(0, 1, 0), 4, &proceeds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gains) != 1 || gains[0].Gain.Get() != 200 {
+		t.Errorf("Sell gains = %+v, want one gain of 200", gains)
+	}
+	var to Ledger
+	if err := l.Transfer("ABC", 6, &to); err != nil {
+		t.Fatal(err)
+	}
+	lots := to.Lots("ABC")
+	if len(lots) != 1 || lots[0].Quantity != 6 || lots[0].Cost.Get() != 600 {
+		t.Errorf("transferred lots = %+v, want 6 shares costing 600", lots)
+	}
+	if lots := l.Lots("ABC"); len(lots) != 0 {
+		t.Errorf("lots left = %+v, want none", lots)
+	}
+}
@@ -0,0 +1,329 @@
+package money
+
+/*
+The following types and functions are available
+
+TimeSeries values indexed by time, oldest first
+NewTimeSeries builds a TimeSeries checking that times ascend
+  NewTimeSeries(times []time.Time, values []float64) *TimeSeries
+NewMoneySeries builds a TimeSeries from Money values
+  NewMoneySeries(times []time.Time, values []Money) *TimeSeries
+Len number of observations
+  (ts *TimeSeries) Len() int
+Money values as Money
+  (ts *TimeSeries) Money() []Money
+Align keeps only the times common to every series (inner join)
+  Align(ss ...*TimeSeries) []*TimeSeries
+Join puts every series on the union of their times, NaN where missing
+  Join(ss ...*TimeSeries) []*TimeSeries
+FFill replaces NaN values with the last valid value
+  (ts *TimeSeries) FFill() *TimeSeries
+Resample last value in each day, week or month
+  (ts *TimeSeries) Resample(f Frequency) *TimeSeries
+ResampleFunc aggregate of the values in each day, week or month
+  (ts *TimeSeries) ResampleFunc(f Frequency, agg func([]float64) float64) *TimeSeries
+Rolling function of each trailing window of w values
+  (ts *TimeSeries) Rolling(w int, f func([]float64) float64) *TimeSeries
+Lag values shifted k periods later (k < 0 leads)
+  (ts *TimeSeries) Lag(k int) *TimeSeries
+Mean, SD, SDs of the values
+  (ts *TimeSeries) Mean() float64
+  (ts *TimeSeries) SD() float64
+  (ts *TimeSeries) SDs() float64
+CovSeries, RSeries Cov and R of two series aligned on their common times
+  CovSeries(x, y *TimeSeries) float64
+  RSeries(x, y *TimeSeries) (a, b, r float64)
+SimpleReturns, LogReturns periodic returns dated at the end of each period
+  (ts *TimeSeries) SimpleReturns() *TimeSeries
+  (ts *TimeSeries) LogReturns() *TimeSeries
+TotalReturns simple returns of a price series including dividends
+  (ts *TimeSeries) TotalReturns(d *TimeSeries) *TimeSeries
+CumulativeReturns compounded return to date of a return series
+  (ts *TimeSeries) CumulativeReturns() *TimeSeries
+ExcessReturns returns over a risk free series on their common times
+  (ts *TimeSeries) ExcessReturns(rf *TimeSeries) *TimeSeries
+GeoMean, AnnualReturn geometric mean and annualised return of a return series
+  (ts *TimeSeries) GeoMean() float64
+  (ts *TimeSeries) AnnualReturn(n float64) float64
+*/
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// TimeSeries is a series of values indexed by ascending times
+type TimeSeries struct {
+	Times  []time.Time
+	Values []float64
+}
+
+// NewTimeSeries builds a TimeSeries
+// times = strictly ascending observation times
+// values = observation at each time
+// times and values must correspond, be the same len()
+func NewTimeSeries(times []time.Time, values []float64) *TimeSeries {
+	if len(times) != len(values) {
+		panic(NOOR)
+	}
+	for i := 1; i < len(times); i++ {
+		if !times[i].After(times[i-1]) {
+			panic(NOOR)
+		}
+	}
+	return &TimeSeries{Times: times, Values: values}
+}
+
+// NewMoneySeries builds a TimeSeries from Money values (see Money.Get)
+func NewMoneySeries(times []time.Time, values []Money) *TimeSeries {
+	vs := make([]float64, len(values))
+	for i := range values {
+		vs[i] = values[i].Get()
+	}
+	return NewTimeSeries(times, vs)
+}
+
+// Frequency is a resampling period
+type Frequency int
+
+const (
+	Daily  Frequency = iota
+	Weekly           // ISO week
+	Monthly
+)
+
+// Len number of observations
+func (ts *TimeSeries) Len() int {
+	return len(ts.Values)
+}
+
+// SimpleReturns periodic simple returns dated at the end of each period
+func (ts *TimeSeries) SimpleReturns() *TimeSeries {
+	r := SimpleReturns(ts.Values)
+	return &TimeSeries{Times: ts.Times[1:], Values: r}
+}
+
+// LogReturns periodic log returns dated at the end of each period
+func (ts *TimeSeries) LogReturns() *TimeSeries {
+	r := LogReturns(ts.Values)
+	return &TimeSeries{Times: ts.Times[1:], Values: r}
+}
+
+// TotalReturns periodic simple returns including dividends dated at the
+// end of each period (see TotalReturns)
+// d = dividend per share on each payment (or ex) date, summed into the
+// period ending at the first price time on or after it, dividends on or
+// before the first price or after the last are left out
+func (ts *TimeSeries) TotalReturns(d *TimeSeries) *TimeSeries {
+	dv := make([]float64, len(ts.Values))
+	j := 0
+	for i, t := range ts.Times {
+		for ; j < len(d.Times) && !d.Times[j].After(t); j++ {
+			if i > 0 {
+				dv[i] += d.Values[j]
+			}
+		}
+	}
+	r := TotalReturns(ts.Values, dv)
+	return &TimeSeries{Times: ts.Times[1:], Values: r}
+}
+
+// CumulativeReturns compounded return to date of a return series
+func (ts *TimeSeries) CumulativeReturns() *TimeSeries {
+	return &TimeSeries{Times: ts.Times, Values: CumulativeReturns(ts.Values)}
+}
+
+// ExcessReturns returns over the risk free returns rf on the times common
+// to both (see Align)
+func (ts *TimeSeries) ExcessReturns(rf *TimeSeries) *TimeSeries {
+	a := Align(ts, rf)
+	return &TimeSeries{Times: a[0].Times, Values: ExcessReturns(a[0].Values, a[1].Values)}
+}
+
+// GeoMean geometric mean periodic return of a return series
+func (ts *TimeSeries) GeoMean() float64 {
+	return GeoMean(ts.Values)
+}
+
+// AnnualReturn annualised geometric return of a return series
+// n = periods per year ex. Periods(Monthly)
+func (ts *TimeSeries) AnnualReturn(n float64) float64 {
+	return AnnualReturn(ts.Values, n)
+}
+
+// Money values as Money (see Money.Setf)
+func (ts *TimeSeries) Money() []Money {
+	ms := make([]Money, len(ts.Values))
+	for i, v := range ts.Values {
+		ms[i].Setf(v)
+	}
+	return ms
+}
+
+// Align keeps only the times common to every series (inner join)
+// returns the series in the same order, each with the same times
+func Align(ss ...*TimeSeries) []*TimeSeries {
+	if len(ss) == 0 {
+		panic(NOOR)
+	}
+	count := map[int64]int{}
+	for _, ts := range ss {
+		for _, t := range ts.Times {
+			count[t.UnixNano()]++
+		}
+	}
+	out := make([]*TimeSeries, len(ss))
+	for i, ts := range ss {
+		a := &TimeSeries{}
+		for j, t := range ts.Times {
+			if count[t.UnixNano()] == len(ss) {
+				a.Times = append(a.Times, t)
+				a.Values = append(a.Values, ts.Values[j])
+			}
+		}
+		out[i] = a
+	}
+	return out
+}
+
+// Join puts every series on the union of their times (outer join), with
+// NaN where a series has no value, see FFill
+func Join(ss ...*TimeSeries) []*TimeSeries {
+	if len(ss) == 0 {
+		panic(NOOR)
+	}
+	seen := map[int64]time.Time{}
+	for _, ts := range ss {
+		for _, t := range ts.Times {
+			if _, ok := seen[t.UnixNano()]; !ok {
+				seen[t.UnixNano()] = t
+			}
+		}
+	}
+	times := make([]time.Time, 0, len(seen))
+	for _, t := range seen {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	out := make([]*TimeSeries, len(ss))
+	for i, ts := range ss {
+		at := make(map[int64]float64, len(ts.Times))
+		for j, t := range ts.Times {
+			at[t.UnixNano()] = ts.Values[j]
+		}
+		j := &TimeSeries{Times: times, Values: make([]float64, len(times))}
+		for k, t := range times {
+			v, ok := at[t.UnixNano()]
+			if !ok {
+				v = math.NaN()
+			}
+			j.Values[k] = v
+		}
+		out[i] = j
+	}
+	return out
+}
+
+// FFill replaces each NaN with the last valid value before it, leading
+// NaN values are left as they are
+func (ts *TimeSeries) FFill() *TimeSeries {
+	f := &TimeSeries{Times: ts.Times, Values: make([]float64, len(ts.Values))}
+	last := math.NaN()
+	for i, v := range ts.Values {
+		if math.IsNaN(v) {
+			v = last
+		}
+		f.Values[i] = v
+		last = v
+	}
+	return f
+}
+
+// period identifies the day, ISO week or month containing t
+func (f Frequency) period(t time.Time) int {
+	switch f {
+	case Weekly:
+		y, w := t.ISOWeek()
+		return y*100 + w
+	case Monthly:
+		return t.Year()*100 + int(t.Month())
+	}
+	return t.Year()*1000 + t.YearDay()
+}
+
+// Resample last value in each day, week or month, dated at the last
+// observation of the period (prices at period end)
+func (ts *TimeSeries) Resample(f Frequency) *TimeSeries {
+	return ts.ResampleFunc(f, func(v []float64) float64 { return v[len(v)-1] })
+}
+
+// ResampleFunc aggregate of the values in each day, week or month, dated
+// at the last observation of the period
+// agg = aggregation ex. Mean or a sum for returns
+func (ts *TimeSeries) ResampleFunc(f Frequency, agg func([]float64) float64) *TimeSeries {
+	r := &TimeSeries{}
+	start := 0
+	for i := range ts.Times {
+		if i+1 == len(ts.Times) || f.period(ts.Times[i+1]) != f.period(ts.Times[i]) {
+			r.Times = append(r.Times, ts.Times[i])
+			r.Values = append(r.Values, agg(ts.Values[start:i+1]))
+			start = i + 1
+		}
+	}
+	return r
+}
+
+// Rolling applies f to each trailing window of w values, dated at the end
+// of the window, ex. ts.Rolling(30, SD)
+func (ts *TimeSeries) Rolling(w int, f func([]float64) float64) *TimeSeries {
+	if w < 1 {
+		panic(NOOR)
+	}
+	r := &TimeSeries{}
+	for i := w - 1; i < len(ts.Values); i++ {
+		r.Times = append(r.Times, ts.Times[i])
+		r.Values = append(r.Values, f(ts.Values[i-w+1:i+1]))
+	}
+	return r
+}
+
+// Lag shifts values k periods later so that time i holds value i-k,
+// dropping the first k times (k < 0 leads and drops the last -k times)
+func (ts *TimeSeries) Lag(k int) *TimeSeries {
+	n := len(ts.Values)
+	if k >= n || -k >= n {
+		return &TimeSeries{}
+	}
+	if k >= 0 {
+		return &TimeSeries{Times: ts.Times[k:], Values: ts.Values[:n-k]}
+	}
+	return &TimeSeries{Times: ts.Times[:n+k], Values: ts.Values[-k:]}
+}
+
+// Mean of the values
+func (ts *TimeSeries) Mean() float64 {
+	return Mean(ts.Values)
+}
+
+// SD Standard Deviation of the values
+func (ts *TimeSeries) SD() float64 {
+	return SD(ts.Values)
+}
+
+// SDs Standard Deviation of the values as a sample
+func (ts *TimeSeries) SDs() float64 {
+	return SDs(ts.Values)
+}
+
+// CovSeries Covariance of two series on their common times
+func CovSeries(x, y *TimeSeries) float64 {
+	a := Align(x, y)
+	return Cov(a[0].Values, a[1].Values)
+}
+
+// RSeries Regression of y on x over their common times
+func RSeries(x, y *TimeSeries) (a, b, r float64) {
+	s := Align(x, y)
+	return R(s[0].Values, s[1].Values)
+}
@@ -0,0 +1,297 @@
+package money
+
+/*
+The following types and functions are available
+
+Risk Value at Risk and Expected Shortfall (CVaR) in Money, positive for a loss
+r holds periodic simple returns (series[i][t] for a portfolio, see
+CovMatrix), conf the confidence level ex. 0.99, h the horizon in periods
+
+VaRNormal parametric (variance-covariance) VaR of a single position
+  VaRNormal(value *Money, r []float64, conf, h float64) Risk
+VaRHistorical historical simulation VaR of a single position
+  VaRHistorical(value *Money, r []float64, conf, h float64) Risk
+VaRMonteCarlo simulated VaR of a single position
+  (mc *MonteCarlo) VaR(value *Money, r []float64, conf, h float64) Risk
+PortfolioVaRNormal, PortfolioVaRHistorical, PortfolioVaR the same for
+positions held in several assets
+  PortfolioVaRNormal(pos []Money, r [][]float64, conf, h float64) Risk
+  PortfolioVaRHistorical(pos []Money, r [][]float64, conf, h float64) Risk
+  (mc *MonteCarlo) PortfolioVaR(pos []Money, r [][]float64, conf, h float64) Risk
+BacktestVaR Kupiec and Christoffersen tests of VaR against realised P&L
+  BacktestVaR(vars, pnl []Money, conf float64) Backtest
+Kupiec proportion of failures test
+  Kupiec(x, n int, conf float64) (lr, p float64)
+Christoffersen independence of exceptions test
+  Christoffersen(hits []bool) (lr, p float64)
+*/
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Risk loss over a horizon at a confidence level, positive for a loss
+// VaR = loss exceeded with probability 1 - conf
+// ES = expected loss when the VaR is exceeded (CVaR)
+type Risk struct {
+	VaR, ES Money
+}
+
+// VaRNormal parametric VaR of a position with normal returns
+// VaR = value * (z * sd * sqrt(h) - mean * h), z = ICND(conf)
+// ES = value * (sd * sqrt(h) * n(z) / (1 - conf) - mean * h)
+// mean and sd = Mean and SD of r
+func VaRNormal(value *Money, r []float64, conf, h float64) Risk {
+	return PortfolioVaRNormal([]Money{*value}, [][]float64{r}, conf, h)
+}
+
+// VaRHistorical historical simulation VaR of a position
+// VaR = conf quantile of the losses -value * r[t], times sqrt(h)
+// ES = mean of the losses at or beyond the VaR, times sqrt(h)
+// the square root of time scaling assumes independent returns
+func VaRHistorical(value *Money, r []float64, conf, h float64) Risk {
+	return PortfolioVaRHistorical([]Money{*value}, [][]float64{r}, conf, h)
+}
+
+// VaR Monte Carlo VaR of a position, see PortfolioVaR
+func (mc *MonteCarlo) VaR(value *Money, r []float64, conf, h float64) Risk {
+	return mc.PortfolioVaR([]Money{*value}, [][]float64{r}, conf, h)
+}
+
+// PortfolioVaRNormal parametric (variance-covariance) VaR of a portfolio
+// mean(p) = SIGMA pos[i] * mean(r[i])
+// sd(p) = sqrt(pos' * cov * pos), cov = CovMatrix(r, false)
+// VaR = z * sd(p) * sqrt(h) - mean(p) * h, z = ICND(conf)
+// ES = sd(p) * sqrt(h) * n(z) / (1 - conf) - mean(p) * h
+// pos = value held in each asset, pos and r must correspond
+func PortfolioVaRNormal(pos []Money, r [][]float64, conf, h float64) Risk {
+	checkVaR(pos, r, conf, h)
+	cov := CovMatrix(r, false)
+	var mp, vp float64
+	for i := range pos {
+		mp += pos[i].Get() * Mean(finite(r[i]))
+		for j := range pos {
+			vp += pos[i].Get() * cov[i][j] * pos[j].Get()
+		}
+	}
+	sp := math.Sqrt(vp * h)
+	z := ICND(conf)
+	var risk Risk
+	risk.VaR.Setf(z*sp - mp*h)
+	risk.ES.Setf(sp*ND(z)/(1-conf) - mp*h)
+	return risk
+}
+
+// PortfolioVaRHistorical historical simulation VaR of a portfolio
+// pnl[t] = SIGMA pos[i] * r[i][t] revalues today's positions on each past
+// period's returns, periods missing any return are skipped
+// VaR, ES as VaRHistorical on the losses -pnl[t]
+func PortfolioVaRHistorical(pos []Money, r [][]float64, conf, h float64) Risk {
+	checkVaR(pos, r, conf, h)
+	x := completeRows(r)
+	pnl := make([]float64, len(x[0]))
+	for i := range pos {
+		for t, v := range x[i] {
+			pnl[t] += pos[i].Get() * v
+		}
+	}
+	return tailRisk(pnl, conf, math.Sqrt(h))
+}
+
+// PortfolioVaR Monte Carlo VaR of a portfolio
+// log returns ln(1 + r) are taken as multivariate normal with the Mean
+// and CovMatrix of the history over h periods, each of mc.Paths scenarios
+// pnl = SIGMA pos[i] * (e ^ x[i] - 1), x = mean * h + L * z * sqrt(h)
+// L = Cholesky factor of the covariance, z = independent normal draws
+// VaR, ES as VaRHistorical on the simulated losses (no scaling)
+// only Paths, Seed, Antithetic and Workers of mc are used
+func (mc *MonteCarlo) PortfolioVaR(pos []Money, r [][]float64, conf, h float64) Risk {
+	checkVaR(pos, r, conf, h)
+	if mc.Paths <= 0 {
+		panic(NOOR)
+	}
+	x := completeRows(r)
+	mean := make([]float64, len(x))
+	for i := range x {
+		for t, v := range x[i] {
+			x[i][t] = math.Log1p(v)
+		}
+		mean[i] = Mean(x[i]) * h
+	}
+	cov := CovMatrix(x, false)
+	l := cholesky(cov)
+	sh := math.Sqrt(h)
+	pnl := mc.scenarios(len(pos), func(z []float64) float64 {
+		var p float64
+		for i := range pos {
+			xi := mean[i]
+			for k := 0; k <= i; k++ {
+				xi += l[i][k] * z[k] * sh
+			}
+			p += pos[i].Get() * math.Expm1(xi)
+		}
+		return p
+	})
+	return tailRisk(pnl, conf, 1)
+}
+
+// scenarios value of f for each of mc.Paths draws of dim normals, in
+// the seeded blocks of Price (see blocks)
+func (mc *MonteCarlo) scenarios(dim int, f func(z []float64) float64) []float64 {
+	draws := mc.Paths
+	per := 1
+	if mc.Antithetic {
+		draws = (draws + 1) / 2
+		per = 2
+	}
+	out := make([]float64, draws*per)
+	mc.blocks(draws, func() func(b int, rng *rand.Rand, n int) {
+		z := make([]float64, dim)
+		return func(b int, rng *rand.Rand, n int) {
+			for d := b * mcBlock; d < b*mcBlock+n; d++ {
+				for j := range z {
+					z[j] = rng.NormFloat64()
+				}
+				out[d*per] = f(z)
+				if mc.Antithetic {
+					for j := range z {
+						z[j] = -z[j]
+					}
+					out[d*per+1] = f(z)
+				}
+			}
+		}
+	})
+	return out
+}
+
+// tailRisk VaR and ES of the profits pnl, both times scale
+func tailRisk(pnl []float64, conf, scale float64) Risk {
+	loss := make([]float64, len(pnl))
+	for i, p := range pnl {
+		loss[i] = -p
+	}
+	v := Quantile(loss, conf, Linear)
+	var sum, n float64
+	for _, l := range loss {
+		if l >= v {
+			sum += l
+			n++
+		}
+	}
+	var risk Risk
+	risk.VaR.Setf(v * scale)
+	risk.ES.Setf(sum / n * scale)
+	return risk
+}
+
+// checkVaR panics on inputs out of range
+func checkVaR(pos []Money, r [][]float64, conf, h float64) {
+	if len(pos) == 0 || len(pos) != len(r) || conf <= 0 || conf >= 1 || h <= 0 {
+		panic(NOOR)
+	}
+}
+
+// finite values of a that are not NaN
+func finite(a []float64) []float64 {
+	f := make([]float64, 0, len(a))
+	for _, v := range a {
+		if !math.IsNaN(v) {
+			f = append(f, v)
+		}
+	}
+	return f
+}
+
+// Backtest of a VaR model against realised P&L
+// Exceptions = periods whose loss exceeded the VaR, of N periods
+// LRuc, Puc = Kupiec unconditional coverage statistic and p-value
+// LRind, Pind = Christoffersen independence statistic and p-value
+// LRcc, Pcc = conditional coverage LRuc + LRind and its p-value
+// a small p-value (ex. < 0.05) rejects the model
+type Backtest struct {
+	N, Exceptions int
+	Rate          float64
+	LRuc, Puc     float64
+	LRind, Pind   float64
+	LRcc, Pcc     float64
+}
+
+// BacktestVaR tests a series of VaR forecasts against the P&L realised
+// vars = VaR forecast for each period (positive for a loss)
+// pnl = realised profit (negative for a loss) over the same periods
+// conf = confidence level of the VaR
+// vars and pnl must correspond, be the same len()
+func BacktestVaR(vars, pnl []Money, conf float64) Backtest {
+	if len(vars) == 0 || len(vars) != len(pnl) {
+		panic(NOOR)
+	}
+	hits := make([]bool, len(vars))
+	x := 0
+	for i := range vars {
+		hits[i] = -pnl[i].Get() > vars[i].Get()
+		if hits[i] {
+			x++
+		}
+	}
+	b := Backtest{N: len(hits), Exceptions: x, Rate: float64(x) / float64(len(hits))}
+	b.LRuc, b.Puc = Kupiec(x, len(hits), conf)
+	b.LRind, b.Pind = Christoffersen(hits)
+	b.LRcc = b.LRuc + b.LRind
+	b.Pcc = 1 - Chi2CD(b.LRcc, 2)
+	return b
+}
+
+// Kupiec proportion of failures likelihood ratio test
+// LR = -2 ln((1 - p)^(n - x) * p^x / ((1 - x/n)^(n - x) * (x/n)^x))
+// p = 1 - conf, expected exception rate
+// x = exceptions in n periods
+// returns LR and its chi-square (1 degree of freedom) p-value
+func Kupiec(x, n int, conf float64) (lr, p float64) {
+	if n <= 0 || x < 0 || x > n || conf <= 0 || conf >= 1 {
+		panic(NOOR)
+	}
+	q := 1 - conf
+	fx, fn := float64(x), float64(n)
+	lr = -2 * (xlogy(fn-fx, 1-q) + xlogy(fx, q) - xlogy(fn-fx, 1-fx/fn) - xlogy(fx, fx/fn))
+	lr = math.Max(lr, 0)
+	return lr, 1 - Chi2CD(lr, 1)
+}
+
+// Christoffersen independence likelihood ratio test, whether an exception
+// is more likely the period after an exception
+// nij = periods in state j following state i (1 = exception)
+// p01 = n01 / (n00 + n01), p11 = n11 / (n10 + n11)
+// p = (n01 + n11) / (n00 + n01 + n10 + n11)
+// LR = -2 ln(L(p) / L(p01, p11))
+// returns LR and its chi-square (1 degree of freedom) p-value
+func Christoffersen(hits []bool) (lr, p float64) {
+	var n [2][2]float64
+	for t := 1; t < len(hits); t++ {
+		i, j := 0, 0
+		if hits[t-1] {
+			i = 1
+		}
+		if hits[t] {
+			j = 1
+		}
+		n[i][j]++
+	}
+	p01 := n[0][1] / (n[0][0] + n[0][1])
+	p11 := n[1][1] / (n[1][0] + n[1][1])
+	pi := (n[0][1] + n[1][1]) / (n[0][0] + n[0][1] + n[1][0] + n[1][1])
+	l0 := xlogy(n[0][0]+n[1][0], 1-pi) + xlogy(n[0][1]+n[1][1], pi)
+	l1 := xlogy(n[0][0], 1-p01) + xlogy(n[0][1], p01) +
+		xlogy(n[1][0], 1-p11) + xlogy(n[1][1], p11)
+	lr = math.Max(-2*(l0-l1), 0)
+	return lr, 1 - Chi2CD(lr, 1)
+}
+
+// xlogy x * ln(y), 0 when x is 0
+func xlogy(x, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return x * math.Log(y)
+}
@@ -0,0 +1,144 @@
+package money
+
+/*
+The following types and functions are available
+
+Bar open, high, low and close prices of one period
+VolCC Close-to-close volatility (SDs of log returns)
+  VolCC(closes []float64, n float64) float64
+VolEWMA Exponentially weighted volatility (RiskMetrics)
+  VolEWMA(closes []float64, lambda, n float64) float64
+VolParkinson Parkinson high-low volatility
+  VolParkinson(bars []Bar, n float64) float64
+VolGK Garman-Klass volatility
+  VolGK(bars []Bar, n float64) float64
+VolRS Rogers-Satchell volatility
+  VolRS(bars []Bar, n float64) float64
+VolYZ Yang-Zhang volatility
+  VolYZ(bars []Bar, n float64) float64
+
+n is the number of periods per year used to annualise, ex. TradingDays
+for daily prices, 52 for weekly or 12 for monthly
+*/
+
+import "math"
+
+// TradingDays is the number of trading days in a year
+const TradingDays = 252
+
+// RiskMetrics is the RiskMetrics decay factor for daily returns
+const RiskMetrics = 0.94
+
+// Bar is the open, high, low and close price of one period
+type Bar struct {
+	Open, High, Low, Close float64
+}
+
+// VolCC Close-to-close volatility
+// v = SDs(ln(c[i] / c[i-1])) * n^1/2
+// closes = closing prices, oldest first
+// n = periods per year
+func VolCC(closes []float64, n float64) float64 {
+	if len(closes) < 3 {
+		panic(NOOR)
+	}
+	return SDs(LogReturns(closes)) * math.Sqrt(n)
+}
+
+// VolEWMA Exponentially weighted moving average volatility (RiskMetrics)
+// v^2(t) = lambda * v^2(t-1) + (1 - lambda) * r^2(t)
+// r = ln(c[i] / c[i-1]), v^2 seeded with the first r^2
+// closes = closing prices, oldest first
+// lambda = decay factor ex. RiskMetrics 0.94 for daily returns
+// n = periods per year
+// returns the annualised volatility after the last close
+func VolEWMA(closes []float64, lambda, n float64) float64 {
+	if len(closes) < 2 || lambda < 0 || lambda >= 1 {
+		panic(NOOR)
+	}
+	rs := LogReturns(closes)
+	v2 := rs[0] * rs[0]
+	for _, r := range rs[1:] {
+		v2 = lambda*v2 + (1-lambda)*r*r
+	}
+	return math.Sqrt(v2 * n)
+}
+
+// VolParkinson Parkinson high-low volatility
+// v^2 = SIGMA ln(h/l)^2 / (4 * ln(2) * N)
+// bars = price bars, N = len(bars)
+// n = periods per year
+func VolParkinson(bars []Bar, n float64) float64 {
+	if len(bars) == 0 {
+		panic(NOOR)
+	}
+	var sum float64
+	for _, b := range bars {
+		hl := math.Log(b.High / b.Low)
+		sum += hl * hl
+	}
+	return math.Sqrt(sum / (4 * math.Ln2 * float64(len(bars))) * n)
+}
+
+// VolGK Garman-Klass volatility
+// v^2 = SIGMA [ln(h/l)^2 / 2 - (2 * ln(2) - 1) * ln(c/o)^2] / N
+// bars = price bars, N = len(bars)
+// n = periods per year
+func VolGK(bars []Bar, n float64) float64 {
+	if len(bars) == 0 {
+		panic(NOOR)
+	}
+	var sum float64
+	for _, b := range bars {
+		hl := math.Log(b.High / b.Low)
+		co := math.Log(b.Close / b.Open)
+		sum += hl*hl/2 - (2*math.Ln2-1)*co*co
+	}
+	return math.Sqrt(sum / float64(len(bars)) * n)
+}
+
+// VolRS Rogers-Satchell volatility (allows for drift)
+// v^2 = SIGMA [ln(h/c) * ln(h/o) + ln(l/c) * ln(l/o)] / N
+// bars = price bars, N = len(bars)
+// n = periods per year
+func VolRS(bars []Bar, n float64) float64 {
+	if len(bars) == 0 {
+		panic(NOOR)
+	}
+	return math.Sqrt(rs2(bars) * n)
+}
+
+// VolYZ Yang-Zhang volatility (allows for drift and opening jumps)
+// v^2 = vo^2 + k * vc^2 + (1 - k) * vrs^2
+// vo^2 = sample variance of ln(o[i] / c[i-1]) (overnight)
+// vc^2 = sample variance of ln(c[i] / o[i]) (open to close)
+// vrs^2 = Rogers-Satchell variance
+// k = 0.34 / (1.34 + (N + 1) / (N - 1))
+// bars = price bars, the first only supplies the previous close, N = len(bars) - 1
+// n = periods per year
+func VolYZ(bars []Bar, n float64) float64 {
+	if len(bars) < 3 {
+		panic(NOOR)
+	}
+	N := float64(len(bars) - 1)
+	over := make([]float64, len(bars)-1)
+	oc := make([]float64, len(bars)-1)
+	for i := 1; i < len(bars); i++ {
+		over[i-1] = math.Log(bars[i].Open / bars[i-1].Close)
+		oc[i-1] = math.Log(bars[i].Close / bars[i].Open)
+	}
+	vo := SDs(over)
+	vc := SDs(oc)
+	k := 0.34 / (1.34 + (N+1)/(N-1))
+	return math.Sqrt((vo*vo + k*vc*vc + (1-k)*rs2(bars[1:])) * n)
+}
+
+// rs2 Rogers-Satchell variance per period
+func rs2(bars []Bar) float64 {
+	var sum float64
+	for _, b := range bars {
+		sum += math.Log(b.High/b.Close)*math.Log(b.High/b.Open) +
+			math.Log(b.Low/b.Close)*math.Log(b.Low/b.Open)
+	}
+	return sum / float64(len(bars))
+}
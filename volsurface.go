@@ -0,0 +1,213 @@
+package money
+
+/*
+The following types and functions are available
+
+VolQuote implied volatility of one option (expiry, strike)
+NewVolSurface builds a VolSurface from implied volatility quotes
+  NewVolSurface(s, r, q float64, quotes []VolQuote) *VolSurface
+Vol implied volatility for any strike and expiry
+  (vs *VolSurface) Vol(k, t float64) float64
+BSM Black-Scholes-Merton premium using the surface volatility
+  (vs *VolSurface) BSM(k, t float64, o OptionType) float64
+Arbitrage calendar and butterfly arbitrage found on the surface
+  (vs *VolSurface) Arbitrage() []VolArbitrage
+*/
+
+import (
+	"math"
+	"sort"
+)
+
+// VolQuote is the implied volatility of one option
+// T = time to expire in years
+// K = strike price
+// Vol = implied volatility (sigma)
+type VolQuote struct {
+	T, K, Vol float64
+}
+
+// volSlice implied volatilities for one expiry as a natural cubic spline
+// in log-moneyness x = ln(k/f)
+type volSlice struct {
+	t  float64
+	xs []float64
+	vs []float64
+	m  []float64 // spline second derivatives
+}
+
+// VolSurface implied volatility surface
+// S = Spot price
+// R = risk free rate
+// Q = dividend yield
+// in strike: natural cubic spline of the volatility in log-moneyness
+// ln(k/f), f = s * e^((r-q)t), held flat beyond the quoted strikes
+// in time: linear in total variance v^2 * t at constant log-moneyness,
+// with flat volatility before the first and after the last expiry
+type VolSurface struct {
+	S, R, Q float64
+	slices  []volSlice
+}
+
+// VolArbitrage is an arbitrage found on a VolSurface
+// Calendar = total variance falls from expiry T to the next (Butterfly
+// when call prices are not convex in strike at expiry T)
+// T = expiry in years
+// K = strike at which it was found
+type VolArbitrage struct {
+	Calendar bool
+	T, K     float64
+}
+
+// NewVolSurface builds a VolSurface from implied volatility quotes
+// s, r, q = spot, risk free rate and dividend yield used for the forwards
+// quotes = implied volatilities, grouped by T into expiry slices
+func NewVolSurface(s, r, q float64, quotes []VolQuote) *VolSurface {
+	if s <= 0 || len(quotes) == 0 {
+		panic(NOOR)
+	}
+	byT := map[float64][]VolQuote{}
+	for _, vq := range quotes {
+		if vq.T <= 0 || vq.K <= 0 || vq.Vol <= 0 {
+			panic(NOOR)
+		}
+		byT[vq.T] = append(byT[vq.T], vq)
+	}
+	vs := &VolSurface{S: s, R: r, Q: q}
+	for t, qs := range byT {
+		sort.Slice(qs, func(i, j int) bool { return qs[i].K < qs[j].K })
+		sl := volSlice{t: t}
+		f := vs.forward(t)
+		for _, vq := range qs {
+			x := math.Log(vq.K / f)
+			if n := len(sl.xs); n > 0 && sl.xs[n-1] == x {
+				panic(NOOR)
+			}
+			sl.xs = append(sl.xs, x)
+			sl.vs = append(sl.vs, vq.Vol)
+		}
+		sl.m = naturalSpline(sl.xs, sl.vs)
+		vs.slices = append(vs.slices, sl)
+	}
+	sort.Slice(vs.slices, func(i, j int) bool { return vs.slices[i].t < vs.slices[j].t })
+	return vs
+}
+
+// forward price for expiry t
+func (vs *VolSurface) forward(t float64) float64 {
+	return vs.S * math.Exp((vs.R-vs.Q)*t)
+}
+
+// Vol implied volatility for strike k and expiry t
+func (vs *VolSurface) Vol(k, t float64) float64 {
+	if k <= 0 || t <= 0 {
+		panic(NOOR)
+	}
+	x := math.Log(k / vs.forward(t))
+	sl := vs.slices
+	if t <= sl[0].t {
+		return sl[0].vol(x)
+	}
+	last := len(sl) - 1
+	if t >= sl[last].t {
+		return sl[last].vol(x)
+	}
+	i := sort.Search(len(sl), func(i int) bool { return sl[i].t >= t }) - 1
+	w1 := math.Pow(sl[i].vol(x), 2) * sl[i].t
+	w2 := math.Pow(sl[i+1].vol(x), 2) * sl[i+1].t
+	w := w1 + (t-sl[i].t)/(sl[i+1].t-sl[i].t)*(w2-w1)
+	return math.Sqrt(math.Max(w, 0) / t)
+}
+
+// BSM Black-Scholes-Merton premium using the surface volatility at k, t
+func (vs *VolSurface) BSM(k, t float64, o OptionType) float64 {
+	return BSM(vs.S, k, t, vs.R, vs.Q, vs.Vol(k, t), o)
+}
+
+// vol of the slice at log-moneyness x
+func (sl volSlice) vol(x float64) float64 {
+	return splineAt(sl.xs, sl.vs, sl.m, x)
+}
+
+// Arbitrage checks the surface on log-moneyness grids spanning the quotes
+// calendar: total variance v^2 * t must not fall between expiries
+// butterfly: undiscounted call prices must be convex in strike within the
+// quoted strikes of each expiry, c(k - h) - 2c(k) + c(k + h) >= 0
+func (vs *VolSurface) Arbitrage() []VolArbitrage {
+	const n = 100
+	const tol = 1e-10
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, sl := range vs.slices {
+		lo = math.Min(lo, sl.xs[0])
+		hi = math.Max(hi, sl.xs[len(sl.xs)-1])
+	}
+	var arb []VolArbitrage
+	for i := 0; i+1 < len(vs.slices); i++ {
+		sl, next := vs.slices[i], vs.slices[i+1]
+		for j := 0; j <= n; j++ {
+			x := lo + float64(j)*(hi-lo)/n
+			if next.vol(x)*next.vol(x)*next.t < sl.vol(x)*sl.vol(x)*sl.t-tol {
+				arb = append(arb, VolArbitrage{Calendar: true, T: sl.t, K: vs.forward(sl.t) * math.Exp(x)})
+			}
+		}
+	}
+	for _, sl := range vs.slices {
+		f := vs.forward(sl.t)
+		x0, x1 := sl.xs[0], sl.xs[len(sl.xs)-1]
+		h := (x1 - x0) / n
+		c := func(x float64) float64 {
+			return BSM(f, f*math.Exp(x), sl.t, 0, 0, sl.vol(x), Call)
+		}
+		for j := 1; j < n; j++ {
+			x := x0 + float64(j)*h
+			// equal steps in strike about k = f * e^x
+			k := f * math.Exp(x)
+			dk := k * h
+			if c(math.Log((k-dk)/f))-2*c(x)+c(math.Log((k+dk)/f)) < -tol*f {
+				arb = append(arb, VolArbitrage{T: sl.t, K: k})
+			}
+		}
+	}
+	return arb
+}
+
+// naturalSpline returns the second derivatives of the natural cubic spline
+// through (xs, ys)
+func naturalSpline(xs, ys []float64) []float64 {
+	n := len(xs)
+	m := make([]float64, n)
+	if n < 3 {
+		return m
+	}
+	// tridiagonal system for m[1..n-2], m[0] = m[n-1] = 0
+	c := make([]float64, n)
+	d := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		h0, h1 := xs[i]-xs[i-1], xs[i+1]-xs[i]
+		a, b := h0/6, (h0+h1)/3
+		rhs := (ys[i+1]-ys[i])/h1 - (ys[i]-ys[i-1])/h0
+		den := b - a*c[i-1]
+		c[i] = h1 / 6 / den
+		d[i] = (rhs - a*d[i-1]) / den
+	}
+	for i := n - 2; i >= 1; i-- {
+		m[i] = d[i] - c[i]*m[i+1]
+	}
+	return m
+}
+
+// splineAt evaluates the cubic spline at x, flat beyond the end knots
+func splineAt(xs, ys, m []float64, x float64) float64 {
+	n := len(xs)
+	if n == 1 || x <= xs[0] {
+		return ys[0]
+	}
+	if x >= xs[n-1] {
+		return ys[n-1]
+	}
+	i := sort.SearchFloat64s(xs, x) - 1
+	h := xs[i+1] - xs[i]
+	a := (xs[i+1] - x) / h
+	b := (x - xs[i]) / h
+	return a*ys[i] + b*ys[i+1] + ((a*a*a-a)*m[i]+(b*b*b-b)*m[i+1])*h*h/6
+}
@@ -0,0 +1,422 @@
+package money
+
+/*
+The following types and functions are available
+
+DayCount accrual basis for money market and swap instruments (Act360, Act365)
+  (dc DayCount) YearFrac(d1, d2 time.Time) float64
+Interpolator pluggable interpolation on the curve knots
+  LinearZero    linear on continuously compounded zero rates
+  LogLinearDF   linear on the log of discount factors
+  MonotoneCubic monotone (Fritsch-Carlson) cubic on zero rates
+Instrument curve building inputs
+  Deposit, FRA, Future, Swap
+Bootstrap builds a YieldCurve from a set of instruments
+  Bootstrap(date time.Time, basis DayCount, interp Interpolator, ins []Instrument) (*YieldCurve, error)
+NewYieldCurve builds a YieldCurve from known discount factors
+  NewYieldCurve(date time.Time, ts, dfs []float64, interp Interpolator) *YieldCurve
+DF Discount factor for time t in years (DFAt for a date)
+  (yc *YieldCurve) DF(t float64) float64
+Zero continuously compounded zero rate for time t in years (ZeroAt for a date)
+  (yc *YieldCurve) Zero(t float64) float64
+Forward continuously compounded forward rate between t1 and t2 (ForwardAt for dates)
+  (yc *YieldCurve) Forward(t1, t2 float64) float64
+PVc Present Value of a Series of cash flows discounted on a YieldCurve
+  (m *Money) PVc(fvs []Money, yc *YieldCurve, ns []float64) *Money
+*/
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DayCount is the accrual basis used to turn two dates into a year fraction
+type DayCount int
+
+const (
+	Act360 DayCount = iota // actual days / 360
+	Act365                 // actual days / 365 (fixed)
+)
+
+// YearFrac returns the year fraction between d1 and d2 under the basis dc
+func (dc DayCount) YearFrac(d1, d2 time.Time) float64 {
+	days := days(d1, d2)
+	if dc == Act360 {
+		return days / 360
+	}
+	return days / 365
+}
+
+// days returns the number of calendar days from d1 to d2 (ignoring clock time)
+func days(d1, d2 time.Time) float64 {
+	u1 := time.Date(d1.Year(), d1.Month(), d1.Day(), 0, 0, 0, 0, time.UTC)
+	u2 := time.Date(d2.Year(), d2.Month(), d2.Day(), 0, 0, 0, 0, time.UTC)
+	return math.Round(u2.Sub(u1).Hours() / 24)
+}
+
+// Interpolator returns the discount factor at time t given the curve knots
+// ts = knot times in years, ascending, ts[0] = 0
+// dfs = discount factors at the knots, dfs[0] = 1
+// beyond the last knot the zero rate is held flat
+type Interpolator interface {
+	DF(ts, dfs []float64, t float64) float64
+}
+
+// LinearZero interpolates linearly on continuously compounded zero rates
+type LinearZero struct{}
+
+// LogLinearDF interpolates linearly on log discount factors
+// (piecewise flat instantaneous forward rates)
+type LogLinearDF struct{}
+
+// MonotoneCubic interpolates zero rates with a Fritsch-Carlson monotone
+// cubic Hermite spline, which does not overshoot between knots
+type MonotoneCubic struct{}
+
+// DF linear zero rate interpolation
+func (LinearZero) DF(ts, dfs []float64, t float64) float64 {
+	zs := zeros(ts, dfs)
+	i, ok := knot(ts, t)
+	if !ok {
+		return math.Exp(-zs[i] * t)
+	}
+	w := (t - ts[i]) / (ts[i+1] - ts[i])
+	return math.Exp(-(zs[i] + w*(zs[i+1]-zs[i])) * t)
+}
+
+// DF log-linear discount factor interpolation
+func (LogLinearDF) DF(ts, dfs []float64, t float64) float64 {
+	i, ok := knot(ts, t)
+	if !ok {
+		last := len(ts) - 1
+		return math.Exp(math.Log(dfs[last]) / ts[last] * t)
+	}
+	w := (t - ts[i]) / (ts[i+1] - ts[i])
+	return math.Exp(math.Log(dfs[i]) + w*(math.Log(dfs[i+1])-math.Log(dfs[i])))
+}
+
+// DF monotone cubic zero rate interpolation
+func (MonotoneCubic) DF(ts, dfs []float64, t float64) float64 {
+	zs := zeros(ts, dfs)
+	i, ok := knot(ts, t)
+	if !ok {
+		return math.Exp(-zs[i] * t)
+	}
+	return math.Exp(-hermite(ts, zs, fritschCarlson(ts, zs), i, t) * t)
+}
+
+// zeros converts knot discount factors into zero rates, the rate at t = 0
+// is taken as the rate of the first knot
+func zeros(ts, dfs []float64) []float64 {
+	zs := make([]float64, len(ts))
+	for i := 1; i < len(ts); i++ {
+		zs[i] = -math.Log(dfs[i]) / ts[i]
+	}
+	if len(zs) > 1 {
+		zs[0] = zs[1]
+	}
+	return zs
+}
+
+// knot returns the index i such that ts[i] <= t < ts[i+1], or the last index
+// and false when t lies beyond the last knot
+func knot(ts []float64, t float64) (int, bool) {
+	last := len(ts) - 1
+	if t >= ts[last] {
+		return last, false
+	}
+	i := sort.SearchFloat64s(ts, t)
+	if i > 0 && (i == len(ts) || ts[i] > t) {
+		i--
+	}
+	return i, true
+}
+
+// fritschCarlson returns the knot slopes of a monotone cubic Hermite spline
+func fritschCarlson(xs, ys []float64) []float64 {
+	n := len(xs)
+	ms := make([]float64, n)
+	if n < 2 {
+		return ms
+	}
+	d := make([]float64, n-1)
+	for i := 0; i < n-1; i++ {
+		d[i] = (ys[i+1] - ys[i]) / (xs[i+1] - xs[i])
+	}
+	ms[0], ms[n-1] = d[0], d[n-2]
+	for i := 1; i < n-1; i++ {
+		if d[i-1]*d[i] <= 0 {
+			ms[i] = 0
+			continue
+		}
+		w1 := 2*(xs[i+1]-xs[i]) + (xs[i] - xs[i-1])
+		w2 := (xs[i+1] - xs[i]) + 2*(xs[i]-xs[i-1])
+		ms[i] = (w1 + w2) / (w1/d[i-1] + w2/d[i])
+	}
+	return ms
+}
+
+// hermite evaluates the cubic Hermite segment i at x
+func hermite(xs, ys, ms []float64, i int, x float64) float64 {
+	h := xs[i+1] - xs[i]
+	s := (x - xs[i]) / h
+	s2, s3 := s*s, s*s*s
+	return (2*s3-3*s2+1)*ys[i] + (s3-2*s2+s)*h*ms[i] +
+		(-2*s3+3*s2)*ys[i+1] + (s3-s2)*h*ms[i+1]
+}
+
+// YieldCurve is a discount curve built from knots in years from Date
+// ts = knot times in years (Act365) from Date, ts[0] = 0
+// dfs = discount factors at the knots, dfs[0] = 1
+type YieldCurve struct {
+	Date   time.Time
+	Basis  DayCount // accrual basis of the instruments
+	Interp Interpolator
+	ts     []float64
+	dfs    []float64
+}
+
+// NewYieldCurve builds a YieldCurve from known discount factors
+// ts = times in years (ascending, greater than zero)
+// dfs = discount factors at ts
+// interp = interpolation method, LogLinearDF if nil
+func NewYieldCurve(date time.Time, ts, dfs []float64, interp Interpolator) *YieldCurve {
+	if len(ts) == 0 || len(ts) != len(dfs) {
+		panic(NOOR)
+	}
+	if interp == nil {
+		interp = LogLinearDF{}
+	}
+	yc := &YieldCurve{Date: date, Basis: Act365, Interp: interp,
+		ts: []float64{0}, dfs: []float64{1}}
+	for i := range ts {
+		if ts[i] <= yc.ts[len(yc.ts)-1] || dfs[i] <= 0 {
+			panic(NOOR)
+		}
+		yc.ts = append(yc.ts, ts[i])
+		yc.dfs = append(yc.dfs, dfs[i])
+	}
+	return yc
+}
+
+// YearFrac time in years (Act365) from the curve date to d
+func (yc *YieldCurve) YearFrac(d time.Time) float64 {
+	return days(yc.Date, d) / 365
+}
+
+// DF Discount factor
+// df = e ^ (-z * t)
+// t = time in years from the curve date
+func (yc *YieldCurve) DF(t float64) float64 {
+	if t <= 0 || len(yc.ts) < 2 {
+		return 1
+	}
+	return yc.Interp.DF(yc.ts, yc.dfs, t)
+}
+
+// Zero continuously compounded zero rate
+// z = -ln(df) / t
+// t = time in years from the curve date
+func (yc *YieldCurve) Zero(t float64) float64 {
+	if t <= 0 {
+		t = 1.0 / 365
+	}
+	return -math.Log(yc.DF(t)) / t
+}
+
+// Forward continuously compounded forward rate
+// f = ln(df1 / df2) / (t2 - t1)
+// t1, t2 = start and end times in years from the curve date
+func (yc *YieldCurve) Forward(t1, t2 float64) float64 {
+	if t2 <= t1 {
+		return yc.Zero(t1)
+	}
+	return math.Log(yc.DF(t1)/yc.DF(t2)) / (t2 - t1)
+}
+
+// DFAt Discount factor for date d
+func (yc *YieldCurve) DFAt(d time.Time) float64 {
+	return yc.DF(yc.YearFrac(d))
+}
+
+// ZeroAt zero rate for date d
+func (yc *YieldCurve) ZeroAt(d time.Time) float64 {
+	return yc.Zero(yc.YearFrac(d))
+}
+
+// ForwardAt forward rate between dates d1 and d2
+func (yc *YieldCurve) ForwardAt(d1, d2 time.Time) float64 {
+	return yc.Forward(yc.YearFrac(d1), yc.YearFrac(d2))
+}
+
+// Instrument is a market quote used to bootstrap a YieldCurve
+// the curve is solved so that each instrument prices at par in turn
+type Instrument interface {
+	Maturity() time.Time
+	// parErr is zero when yc reprices the instrument
+	parErr(yc *YieldCurve) float64
+}
+
+// Deposit money market deposit starting on the curve date
+// df(mat) = 1 / (1 + r * tau)
+type Deposit struct {
+	Mat  time.Time
+	Rate float64 // simple rate in decimal percent
+}
+
+// FRA forward rate agreement from Start to End
+// df(end) = df(start) / (1 + r * tau)
+type FRA struct {
+	Start, End time.Time
+	Rate       float64 // simple forward rate in decimal percent
+}
+
+// Future interest rate future on the period Start to End
+// r = (100 - price) / 100 - convexity
+type Future struct {
+	Start, End time.Time
+	Price      float64 // quoted price ex. 97.25
+	Convexity  float64 // convexity adjustment in decimal percent
+}
+
+// Swap par fixed/floating swap starting on the curve date
+// 1 - df(tn) = S * SIGMA tau-sub(i) * df(t-sub(i))
+type Swap struct {
+	Mat  time.Time
+	Rate float64 // par fixed rate in decimal percent
+	Freq int     // fixed payments per year 1, 2, 3, 4, 6 or 12, 1 if zero
+}
+
+func (d Deposit) Maturity() time.Time { return d.Mat }
+func (f FRA) Maturity() time.Time     { return f.End }
+func (f Future) Maturity() time.Time  { return f.End }
+func (s Swap) Maturity() time.Time    { return s.Mat }
+
+func (d Deposit) parErr(yc *YieldCurve) float64 {
+	return yc.DFAt(d.Mat)*(1+d.Rate*yc.Basis.YearFrac(yc.Date, d.Mat)) - 1
+}
+
+func (f FRA) parErr(yc *YieldCurve) float64 {
+	return yc.DFAt(f.End)*(1+f.Rate*yc.Basis.YearFrac(f.Start, f.End)) - yc.DFAt(f.Start)
+}
+
+func (f Future) parErr(yc *YieldCurve) float64 {
+	return FRA{f.Start, f.End, (100-f.Price)/100 - f.Convexity}.parErr(yc)
+}
+
+func (s Swap) parErr(yc *YieldCurve) float64 {
+	var annuity float64
+	pay := s.schedule(yc.Date)
+	prev := yc.Date
+	for _, d := range pay {
+		annuity += yc.Basis.YearFrac(prev, d) * yc.DFAt(d)
+		prev = d
+	}
+	return s.Rate*annuity + yc.DFAt(s.Mat) - 1
+}
+
+// freq fixed payments per year, 0 if Freq does not divide the year into
+// whole months
+func (s Swap) freq() int {
+	switch s.Freq {
+	case 0:
+		return 1
+	case 1, 2, 3, 4, 6, 12:
+		return s.Freq
+	}
+	return 0
+}
+
+// schedule fixed leg payment dates rolled back from maturity
+func (s Swap) schedule(start time.Time) []time.Time {
+	step := 12 / s.freq()
+	var pay []time.Time
+	for i := 0; ; i++ {
+		d := s.Mat.AddDate(0, -i*step, 0)
+		if days(start, d) <= 0 {
+			break
+		}
+		pay = append([]time.Time{d}, pay...)
+	}
+	return pay
+}
+
+// Bootstrap builds a YieldCurve from a set of instruments
+// date = curve (valuation) date
+// basis = accrual basis of the instrument rates
+// interp = interpolation method, LogLinearDF if nil
+// ins = deposits, FRAs, futures and swaps, one per maturity
+// returns BOOTFREQ for a Swap Freq other than 1, 2, 3, 4, 6 or 12
+// each instrument in maturity order adds a knot whose discount factor is
+// solved so that the instrument reprices at par on the curve so far
+func Bootstrap(date time.Time, basis DayCount, interp Interpolator, ins []Instrument) (*YieldCurve, error) {
+	if interp == nil {
+		interp = LogLinearDF{}
+	}
+	sorted := make([]Instrument, len(ins))
+	copy(sorted, ins)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Maturity().Before(sorted[j].Maturity())
+	})
+	yc := &YieldCurve{Date: date, Basis: basis, Interp: interp,
+		ts: []float64{0}, dfs: []float64{1}}
+	for _, in := range sorted {
+		if s, ok := in.(Swap); ok && s.freq() == 0 {
+			return nil, errors.New(BOOTFREQ + strconv.Itoa(s.Freq))
+		}
+		t := yc.YearFrac(in.Maturity())
+		if t <= yc.ts[len(yc.ts)-1] {
+			return nil, errors.New(BOOTMAT + in.Maturity().Format("2006-01-02"))
+		}
+		yc.ts = append(yc.ts, t)
+		yc.dfs = append(yc.dfs, 1)
+	}
+	// non-local interpolators (MonotoneCubic) move earlier segments as
+	// knots are added, so sweep until every instrument reprices
+	for pass := 0; pass < 100; pass++ {
+		for i, in := range sorted {
+			k := i + 1
+			df, ok := brent(func(df float64) float64 {
+				yc.dfs[k] = df
+				return in.parErr(yc)
+			}, 1e-6, 4, 1e-15)
+			if !ok {
+				return nil, errors.New(BOOTFAIL + in.Maturity().Format("2006-01-02"))
+			}
+			yc.dfs[k] = df
+		}
+		converged := true
+		for _, in := range sorted {
+			if math.Abs(in.parErr(yc)) > 1e-12 {
+				converged = false
+				break
+			}
+		}
+		if converged {
+			return yc, nil
+		}
+	}
+	return nil, errors.New(BOOTFAIL + date.Format("2006-01-02"))
+}
+
+// PVc Present Value of a Series of cash flows discounted on a YieldCurve
+// pv = SIGMA (n, t=0) [fv-sub(t) * df(n-sub(t))]
+// pv = present value of Money
+// fv = future value in the array slice element of fvs
+// df = discount factor read off the curve yc
+// n = time in years from the curve date in array slice ns
+// fvs and ns must correspond, be the same len()
+func (m *Money) PVc(fvs []Money, yc *YieldCurve, ns []float64) *Money {
+	if len(fvs) != len(ns) {
+		panic(NOOR)
+	}
+	m.Set(0)
+	for j := range fvs {
+		fv := fvs[j]
+		m.Add(fv.Setf(fv.Get() * yc.DF(ns[j])))
+	}
+	return m
+}